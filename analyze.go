@@ -0,0 +1,491 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/models"
+	"ship-of-theseus/internal/report"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// runAnalyze implements the analyze subcommand: the full analysis, timeline and report.
+// It is also what runs when ship-of-theseus is invoked without a subcommand.
+func runAnalyze(args []string) int {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+
+	common := addCommonFlags(fs)
+	var (
+		sampleRate  = fs.Int("sample", 50, "Sample every Nth commit for history timeline")
+		showVersion = fs.Bool("version", false, "Show version information")
+		timelineSVG = fs.String("svg-timeline", "", "Write the evolution timeline as an SVG chart to this file")
+		badgeSVG    = fs.String("badge", "", "Write an \"original code\" SVG badge to this file")
+		format      = fs.String("format", "text", "Output format: text, markdown, csv or ndjson")
+		outputPath  = fs.String("output", "", "Write the report to this file instead of stdout (non-text formats)")
+		appendHist  = fs.Bool("append-history", false, "Carry the history section of an existing --output report forward")
+		lineLevel   = fs.Bool("lines", false, "With --format ndjson, emit one object per line instead of per file")
+		saveReport  = fs.String("save-report", "", "Save a compact JSON report of this run to a file")
+		record      = fs.Bool("record", false, "Append this run to the history store, keyed by commit")
+		historyFile = fs.String("history-file", "", "History store for --record (default: <path>/"+report.DefaultHistoryPath+")")
+		writeNotes  = fs.Bool("notes", false, "Store a summary of this run as a git note on HEAD ("+analyzer.NotesRef+")")
+	)
+	watch := addWatchFlags(fs, "refresh the report")
+	originOpts := addOriginFlags(fs)
+	scope := addHistoryFlags(fs)
+	timeline := addTimelineFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Ship of Theseus v%s - Codebase Evolution Analyzer
+
+Usage:
+  ship-of-theseus analyze [options]
+  ship-of-theseus [options]
+
+Options:
+`, version)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Analyzes a git repository to determine how much "original" code remains.
+  Traces each line from its first appearance to its current state, measuring
+  similarity using Levenshtein distance. Lines with ≥25%% similarity are
+  considered "original"; lines that have changed more are "completely different."
+
+  Like the ancient Ship of Theseus paradox: if every line of code is eventually
+  modified, is it still the same codebase?
+
+Examples:
+  # Analyze current directory
+  ship-of-theseus analyze
+
+  # Analyze specific repository with 8 workers
+  ship-of-theseus analyze --path /path/to/repo --workers 8
+
+  # Use coarser sampling for faster analysis
+  ship-of-theseus analyze --sample 100
+
+  # Export a timeline chart and badge for embedding in a README
+  ship-of-theseus analyze --svg-timeline timeline.svg --badge badge.svg
+
+  # Maintain a Markdown status report with a running history
+  ship-of-theseus analyze --format markdown --output SHIP_STATUS.md --append-history
+
+  # Record this run and plot the measured trend across recorded runs
+  ship-of-theseus analyze --record && ship-of-theseus trend
+
+  # Store the summary in git notes and plot the measured timeline they form
+  ship-of-theseus analyze --notes && ship-of-theseus notes timeline
+
+  # Measure how much of the code as of v1.0 (or a date) is still here
+  ship-of-theseus analyze --origin-at v1.0
+  ship-of-theseus analyze --origin-at 2022-06-01
+
+  # Measure how far a fork has drifted from upstream since it diverged
+  ship-of-theseus analyze --upstream upstream/main --merge-base
+
+  # How much of the code as of January 1st was rewritten during 2024?
+  ship-of-theseus analyze --since 2024-01-01 --until 2024-12-31
+
+  # Follow only the mainline of main, ignoring stale branches and merged commits
+  ship-of-theseus analyze --branch main --first-parent
+
+  # Plot the timeline at each release instead of every 50th commit
+  ship-of-theseus analyze --timeline tags --tag-glob "v*"
+
+  # Plot one point per month, comparable across repositories
+  ship-of-theseus analyze --interval month
+
+  # Export per-file rows for a spreadsheet, or every line for pandas/DuckDB
+  ship-of-theseus analyze --format csv --output files.csv
+  ship-of-theseus analyze --format ndjson --lines --output lines.ndjson
+
+Performance:
+  - Small repos (<100 files): <30 seconds
+  - Medium repos (1K-5K files): <5 minutes
+  - Large repos (>10K files): <30 minutes
+
+  Increase --workers for faster analysis on multi-core systems.
+  Increase --sample to reduce analysis time (trades accuracy for speed).
+
+For more information: https://github.com/yourusername/ship-of-theseus
+`)
+	}
+
+	fs.Parse(args)
+
+	// Handle version flag
+	if *showVersion {
+		fmt.Printf("Ship of Theseus v%s\n", version)
+		return 0
+	}
+
+	// Validate and resolve repository path
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	filter, err := scope.filter()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// With --branch or --until, the code at the end of that history is analyzed instead of HEAD
+	analysisPath, removeCheckout, err := scope.checkout(absPath, filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer removeCheckout()
+
+	if watch.enabled && (!filter.Until.IsZero() || analysisPath != absPath) {
+		fmt.Fprintf(os.Stderr, "Error: --watch follows HEAD, so it cannot be combined with --until or another --branch\n")
+		return 1
+	}
+
+	// The origin depends on HEAD and refs, so --watch resolves it again for every change
+	resolveOrigin := func() (analyzer.Origin, error) {
+		switch {
+		case filter.Since.IsZero():
+			origin, err := originOpts.resolve(analysisPath)
+			origin.FirstParent = filter.FirstParent
+			return origin, err
+		case originOpts.originAt != "" || originOpts.upstream != "" || originOpts.mergeBase:
+			return analyzer.Origin{}, fmt.Errorf("--since cannot be combined with --origin-at, --upstream or --merge-base")
+		default:
+			return analyzer.SinceOrigin(analysisPath, filter)
+		}
+	}
+	origin, err := resolveOrigin()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Validate parameters
+	sampling, err := timeline.sampling(*sampleRate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	switch *format {
+	case "text", "markdown", "csv", "ndjson":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: --format must be one of: text, markdown, csv, ndjson\n")
+		return 1
+	}
+
+	if *outputPath != "" && *format == "text" {
+		fmt.Fprintf(os.Stderr, "Error: --output requires a file format such as --format markdown\n")
+		return 1
+	}
+
+	if *appendHist && (*outputPath == "" || *format != "markdown") {
+		fmt.Fprintf(os.Stderr, "Error: --append-history requires --format markdown and --output\n")
+		return 1
+	}
+
+	if *lineLevel && *format != "ndjson" {
+		fmt.Fprintf(os.Stderr, "Error: --lines requires --format ndjson\n")
+		return 1
+	}
+
+	if err := watch.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if watch.enabled && *format != "text" {
+		fmt.Fprintf(os.Stderr, "Error: --watch requires --format text\n")
+		return 1
+	}
+
+	// CSV and NDJSON are streamed: each file is written as soon as it is analyzed
+	streaming := *format == "csv" || *format == "ndjson"
+
+	// Status messages go to stderr when stdout carries a machine-readable report
+	status := os.Stdout
+	if *format != "text" {
+		status = os.Stderr
+	}
+
+	// Print startup message
+	fmt.Fprintf(status, "🚢 Ship of Theseus v%s\n", version)
+	fmt.Fprintf(status, "Analyzing repository: %s\n", absPath)
+	switch {
+	case sampling.Tags:
+		fmt.Fprintf(status, "Workers: %d | Timeline: release tags\n", common.workers)
+	case sampling.Interval != "":
+		fmt.Fprintf(status, "Workers: %d | Timeline: last commit of each %s\n", common.workers, sampling.Interval)
+	default:
+		fmt.Fprintf(status, "Workers: %d | Sample rate: every %d commits\n", common.workers, *sampleRate)
+	}
+	if analysisPath != absPath {
+		if head, err := analyzer.GetHeadCommit(analysisPath); err == nil {
+			fmt.Fprintf(status, "Analyzing the code at: %s (%s)\n", head.Hash[:7], head.Date.Format("2006-01-02"))
+		}
+	}
+	if origin.Commit != "" {
+		fmt.Fprintf(status, "Comparing with: %s\n", origin)
+	}
+	fmt.Fprintln(status)
+
+	// No report of this command shows line-level detail, except what the streamed
+	// formats write as each file is analyzed
+	opts := analyzer.Options{NumWorkers: common.workers, Origin: origin, DropLineHistories: true}
+
+	var stream visualizer.FileWriter
+	var streamFile *os.File
+	if streaming {
+		out := os.Stdout
+		if *outputPath != "" {
+			f, err := os.Create(*outputPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Could not create output file: %v\n", err)
+				return 1
+			}
+			out, streamFile = f, f
+		}
+
+		if *format == "csv" {
+			stream = visualizer.NewCSVWriter(out)
+		} else {
+			stream = visualizer.NewNDJSONWriter(out, *lineLevel)
+		}
+
+		// Line detail has been written by the time the sink returns
+		opts.Sinks = append(opts.Sinks, analyzer.SinkFunc(stream.WriteFile))
+	}
+
+	// Note what is being analyzed first, so --watch can't miss commits made meanwhile
+	var state analyzer.RepoState
+	if watch.enabled {
+		if state, err = analyzer.GetRepoState(absPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	// Run the analysis
+	analysis, err := analyzer.AnalyzeRepositoryWithOptions(analysisPath, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nError during analysis: %v\n", err)
+		return 1
+	}
+
+	if stream != nil {
+		err := stream.Flush()
+		if streamFile != nil {
+			if closeErr := streamFile.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not write %s output: %v\n", *format, err)
+			return 1
+		}
+	}
+
+	// Generate historical snapshots (streamed formats only need them for the SVG chart)
+	if !streaming || *timelineSVG != "" {
+		fmt.Fprintln(status, "\nGenerating historical timeline...")
+		if err := analyzer.AddSnapshotsToAnalysis(analysis, analysisPath, sampling, filter); err != nil {
+			// Non-fatal: continue without snapshots
+			fmt.Fprintf(status, "Warning: Could not generate historical timeline: %v\n", err)
+		}
+	}
+
+	// Persist the run
+	if *saveReport != "" || *record || *writeNotes {
+		r := newReport(analysisPath, analysis)
+		r.Settings.SampleRate = sampling.Every
+
+		if *saveReport != "" {
+			if err := r.Save(*saveReport); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Could not save report: %v\n", err)
+				return 1
+			}
+		}
+
+		if *record {
+			history, err := openHistory(absPath, *historyFile)
+			if err == nil {
+				err = history.Append(r)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Could not record run: %v\n", err)
+				return 1
+			}
+			fmt.Fprintf(status, "Recorded run in %s\n", history.Path())
+		}
+
+		if *writeNotes {
+			note, err := r.MarshalNote()
+			if err == nil && r.CommitHash == "" {
+				err = fmt.Errorf("could not determine HEAD commit")
+			}
+			if err == nil {
+				err = analyzer.AddNote(absPath, r.CommitHash, note)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Could not write git note: %v\n", err)
+				return 1
+			}
+			fmt.Fprintf(status, "Stored summary in %s for %s\n", analyzer.NotesRef, r.CommitHash[:7])
+		}
+	}
+
+	// Export images
+	if *timelineSVG != "" {
+		if len(analysis.HistoricalSnapshots) == 0 {
+			fmt.Fprintf(os.Stderr, "Warning: No timeline available, skipping %s\n", *timelineSVG)
+		} else if err := writeFile(*timelineSVG, func(w io.Writer) error {
+			return visualizer.WriteTimelineSVG(w, analysis.HistoricalSnapshots)
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not write timeline chart: %v\n", err)
+			return 1
+		}
+	}
+
+	if *badgeSVG != "" {
+		originalPct := 0.0
+		if analysis.TotalLines > 0 {
+			originalPct = float64(analysis.OriginalLines) / float64(analysis.TotalLines) * 100.0
+		}
+		if err := writeFile(*badgeSVG, func(w io.Writer) error {
+			return visualizer.WriteBadgeSVG(w, originalPct)
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not write badge: %v\n", err)
+			return 1
+		}
+	}
+
+	// Display results
+	switch *format {
+	case "markdown":
+		if err := writeMarkdownReport(analysisPath, analysis, *outputPath, *timelineSVG, *appendHist); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not write Markdown report: %v\n", err)
+			return 1
+		}
+	case "csv", "ndjson":
+		// Already streamed during the analysis
+	default:
+		visualizer.Display(analysis)
+	}
+
+	if watch.enabled {
+		return watchAnalysis(absPath, analysis, state, analyzer.Options{NumWorkers: common.workers, Origin: origin}, resolveOrigin, sampling, filter, watch.interval)
+	}
+
+	return 0
+}
+
+// watchAnalysis keeps the displayed report current: whenever new commits arrive, the
+// files they touched are re-analyzed and the report is shown again.
+func watchAnalysis(repoPath string, analysis *models.CodebaseAnalysis, state analyzer.RepoState, opts analyzer.Options, resolveOrigin func() (analyzer.Origin, error), sampling analyzer.Sampling, filter analyzer.CommitFilter, interval time.Duration) int {
+	// The text report doesn't show line-level detail
+	opts.DropLineHistories = true
+
+	fmt.Printf("Watching for new commits every %s (Ctrl+C to stop)...\n", interval)
+	err := analyzer.Watch(repoPath, state, interval, func(change analyzer.Change) error {
+		updated, err := refreshAnalysis(repoPath, analysis, change, &opts, resolveOrigin, sampling, filter)
+		if err != nil {
+			return err
+		}
+		analysis = updated
+
+		if isTerminal(os.Stdout) {
+			fmt.Print("\033[H\033[2J")
+		}
+		fmt.Println(describeChange(change))
+		visualizer.Display(analysis)
+		fmt.Printf("Watching for new commits every %s (Ctrl+C to stop)...\n", interval)
+		return nil
+	})
+
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return 1
+}
+
+// refreshAnalysis applies a change to an analysis and regenerates its timeline. The
+// origin is resolved again, unless resolveOrigin is nil, and stored in opts; when it
+// has moved, every file is analyzed again, since none of the previous results hold.
+func refreshAnalysis(repoPath string, prev *models.CodebaseAnalysis, change analyzer.Change, opts *analyzer.Options, resolveOrigin func() (analyzer.Origin, error), sampling analyzer.Sampling, filter analyzer.CommitFilter) (*models.CodebaseAnalysis, error) {
+	var analysis *models.CodebaseAnalysis
+	var err error
+	origin := opts.Origin
+	if resolveOrigin != nil {
+		if origin, err = resolveOrigin(); err != nil {
+			return nil, fmt.Errorf("could not resolve the origin: %w", err)
+		}
+	}
+
+	if origin.Equal(opts.Origin) {
+		analysis, err = analyzer.UpdateAnalysis(repoPath, prev, change, *opts)
+	} else {
+		fmt.Fprintf(os.Stderr, "The origin moved to %s; re-analyzing every file\n\n", origin)
+		opts.Origin = origin
+		analysis, err = analyzer.AnalyzeRepositoryWithOptions(repoPath, *opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("re-analysis failed: %w", err)
+	}
+
+	if err := analyzer.AddSnapshotsToAnalysis(analysis, repoPath, sampling, filter); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not generate historical timeline: %v\n", err)
+	}
+	return analysis, nil
+}
+
+// describeChange summarizes a change for status output.
+func describeChange(change analyzer.Change) string {
+	if change.From.Head == change.To.Head {
+		return "🔄 Refs updated; timeline regenerated"
+	}
+	return fmt.Sprintf("🔄 HEAD %s → %s: %d file(s) changed, %d removed",
+		change.From.Head[:7], change.To.Head[:7], len(change.Changed), len(change.Deleted))
+}
+
+// writeMarkdownReport renders the analysis as Markdown to outputPath (or stdout when empty).
+// A timeline SVG, if one was exported, is embedded using a path relative to the report.
+func writeMarkdownReport(repoPath string, analysis *models.CodebaseAnalysis, outputPath, timelineSVG string, appendHistory bool) error {
+	opts := visualizer.MarkdownOptions{}
+
+	if head, err := analyzer.GetHeadCommit(repoPath); err == nil {
+		opts.CommitHash = head.Hash
+		opts.CommitDate = head.Date
+	}
+
+	if timelineSVG != "" && len(analysis.HistoricalSnapshots) > 0 {
+		opts.TimelineImage = filepath.ToSlash(timelineSVG)
+		if outputPath != "" {
+			if rel, err := filepath.Rel(filepath.Dir(outputPath), timelineSVG); err == nil {
+				opts.TimelineImage = filepath.ToSlash(rel)
+			}
+		}
+	}
+
+	if outputPath == "" {
+		return visualizer.WriteMarkdown(os.Stdout, analysis, opts)
+	}
+
+	if appendHistory {
+		previous, err := os.ReadFile(outputPath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		opts.PreviousReport = string(previous)
+	}
+
+	return writeFile(outputPath, func(w io.Writer) error {
+		return visualizer.WriteMarkdown(w, analysis, opts)
+	})
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// runBlame implements the blame subcommand: git blame annotated with similarity to origin.
+func runBlame(args []string) int {
+	fs := flag.NewFlagSet("blame", flag.ExitOnError)
+	common := addPathFlag(fs)
+	originOpts := addOriginFlags(fs)
+	var (
+		porcelain = fs.Bool("porcelain", false, "Machine-readable output for editor integrations")
+		colorMode = fs.String("color", "auto", "Color lines by originality tier: auto, always or never")
+	)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage:
+  ship-of-theseus blame [options] <path>
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Lists a file as committed at HEAD in the style of git blame. Each code line is
+  prefixed with the last commit to modify it, the commit the file was born in, its
+  similarity to the file's first version and the date of the last change.
+
+  --porcelain writes one record per code line, modeled on git blame --line-porcelain:
+    <last-commit> <birth-commit> <line> <original-line>
+    similarity <0.0000-1.0000>
+    original <true|false>
+    last-commit-time <unix>
+    original-line<TAB><text of the matched line in the first version>
+    <TAB><current line>
+`)
+	}
+
+	positional := parseArgs(fs, args)
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	color, err := colorEnabled(*colorMode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	filePath, err := repoRelativePath(absPath, positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	origin, err := originOpts.resolve(absPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	file, err := analyzer.AnalyzeFile(absPath, filePath, origin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not analyze %s: %v\n", filePath, err)
+		return 1
+	}
+
+	if *porcelain {
+		err = visualizer.WriteBlamePorcelain(os.Stdout, file, analyzer.IsOriginal)
+	} else {
+		var content string
+		content, err = analyzer.GetFileAtCommit(absPath, "HEAD", filePath)
+		if err == nil {
+			err = visualizer.WriteBlame(os.Stdout, file, content, color)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/check"
+	"ship-of-theseus/internal/report"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// Exit statuses of the check subcommand, distinct so CI can tell a failed gate from a
+// misconfigured one. 1 is left for internal errors, such as an analysis that failed.
+const (
+	exitUsage       = 2 // Invalid flags or configuration, like the flag package's own errors
+	exitCheckFailed = 3 // The analysis ran but a quality gate failed
+)
+
+// optionalFloat is a float flag that records whether it was set.
+type optionalFloat struct {
+	value float64
+	set   bool
+}
+
+func (f *optionalFloat) String() string {
+	if !f.set {
+		return ""
+	}
+	return strconv.FormatFloat(f.value, 'f', -1, 64)
+}
+
+func (f *optionalFloat) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return fmt.Errorf("must be a non-negative number")
+	}
+	f.value, f.set = v, true
+	return nil
+}
+
+// ruleList collects repeated --rule flags.
+type ruleList []check.Rule
+
+func (r *ruleList) String() string {
+	return ""
+}
+
+func (r *ruleList) Set(s string) error {
+	rule, err := check.ParseRule(s)
+	if err != nil {
+		return err
+	}
+	*r = append(*r, rule)
+	return nil
+}
+
+// runCheck implements the check subcommand and returns the process exit status.
+func runCheck(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+
+	common := addCommonFlags(fs)
+	var (
+		minOriginal  optionalFloat
+		maxDrop      optionalFloat
+		rules        ruleList
+		baselineFile = fs.String("baseline", "", "Baseline report file to compare against (see --save-report)")
+		baselineRef  = fs.String("baseline-ref", "", "Git ref to analyze as the baseline (e.g. origin/main)")
+		saveReport   = fs.String("save-report", "", "Save the current analysis as a report file (e.g. to use as a future baseline)")
+	)
+	fs.Var(&minOriginal, "min-original", "Fail if overall original code is below this percentage")
+	fs.Var(&maxDrop, "max-drop", "Fail if overall originality dropped more than this many points versus the baseline")
+	fs.Var(&rules, "rule", "Per-path rule `<pattern>:min=N,max-drop=N` (repeatable)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Ship of Theseus v%s - Originality Quality Gate
+
+Usage:
+  ship-of-theseus check [options]
+
+Options:
+`, version)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Analyzes the repository and fails (exit status %d) if any threshold is violated,
+  printing which rule failed. Drops are measured in percentage points against a
+  baseline, given either as a report file or as a git ref that is analyzed in a
+  temporary worktree.
+
+  Invalid options, such as a missing threshold or a baseline that can't be
+  read, exit with status %d; status 1 means an internal error, such as an
+  analysis that failed.
+
+  Rule patterns match paths relative to the repository root: "**" spans
+  directories, "*" matches within a directory, and a trailing "/" matches
+  everything below a directory.
+
+Examples:
+  # Require at least 40%% original code overall
+  ship-of-theseus check --min-original 40
+
+  # Fail a PR that drops originality by more than 5 points versus main
+  ship-of-theseus check --baseline-ref origin/main --max-drop 5
+
+  # Protect audited modules
+  ship-of-theseus check --baseline baseline.json \
+    --rule 'internal/crypto/:min=90,max-drop=1' --rule 'internal/auth/**:max-drop=2'
+`, exitCheckFailed, exitUsage)
+	}
+
+	fs.Parse(args)
+
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	// Overall thresholds are rules without a pattern
+	if minOriginal.set || maxDrop.set {
+		overall := check.Rule{
+			MinOriginal: minOriginal.value, HasMin: minOriginal.set,
+			MaxDrop: maxDrop.value, HasMaxDrop: maxDrop.set,
+		}
+		rules = append(ruleList{overall}, rules...)
+	}
+
+	if len(rules) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no thresholds given (use --min-original, --max-drop or --rule)\n")
+		return exitUsage
+	}
+
+	if *baselineFile != "" && *baselineRef != "" {
+		fmt.Fprintf(os.Stderr, "Error: use either --baseline or --baseline-ref, not both\n")
+		return exitUsage
+	}
+
+	if check.NeedsBaseline(rules) && *baselineFile == "" && *baselineRef == "" {
+		fmt.Fprintf(os.Stderr, "Error: max-drop rules need a baseline (use --baseline or --baseline-ref)\n")
+		return exitUsage
+	}
+
+	// A baseline that can't be used is a configuration error, found before analyzing
+	var baseline *report.Report
+	switch {
+	case *baselineFile != "":
+		baseline, err = report.Load(*baselineFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not load baseline: %v\n", err)
+			return exitUsage
+		}
+	case *baselineRef != "":
+		if _, err := analyzer.ResolveCommit(absPath, *baselineRef); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --baseline-ref: %v\n", err)
+			return exitUsage
+		}
+	}
+
+	fmt.Printf("🚢 Ship of Theseus v%s\n", version)
+	fmt.Printf("Checking repository: %s\n\n", absPath)
+
+	current, err := analyzeReport(absPath, common.workers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nError during analysis: %v\n", err)
+		return 1
+	}
+
+	if *saveReport != "" {
+		if err := current.Save(*saveReport); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not save report: %v\n", err)
+			return 1
+		}
+	}
+
+	if *baselineRef != "" {
+		fmt.Printf("Analyzing baseline %s...\n\n", *baselineRef)
+		err = analyzer.WithWorktree(absPath, *baselineRef, func(worktree string) error {
+			var err error
+			baseline, err = analyzeReport(worktree, common.workers)
+			return err
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\nError analyzing baseline: %v\n", err)
+			return 1
+		}
+	}
+
+	fmt.Println()
+	if baseline != nil {
+		fmt.Printf("Baseline: %s (%.1f%% original)\n\n", describeReport(baseline), baseline.OriginalPct)
+	}
+
+	results := check.Evaluate(current, baseline, rules)
+	visualizer.DisplayCheck(results)
+
+	if !check.Passed(results) {
+		return exitCheckFailed
+	}
+	return 0
+}
+
+// analyzeReport runs a summary-only analysis of the repository at repoPath and
+// returns it as a report tagged with the HEAD commit and tool version.
+func analyzeReport(repoPath string, numWorkers int) (*report.Report, error) {
+	analysis, err := analyzer.AnalyzeRepositoryWithOptions(repoPath, analyzer.Options{
+		NumWorkers:        numWorkers,
+		DropLineHistories: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newReport(repoPath, analysis), nil
+}
+
+// describeReport returns a short human-readable identifier for a report.
+func describeReport(r *report.Report) string {
+	var parts []string
+	if r.CommitHash != "" {
+		parts = append(parts, r.CommitHash[:min(7, len(r.CommitHash))])
+	}
+	if r.CommitDate != nil {
+		parts = append(parts, r.CommitDate.Format("2006-01-02"))
+	}
+	if len(parts) == 0 {
+		return "report from " + r.GeneratedAt.Format("2006-01-02")
+	}
+	return strings.Join(parts, " ")
+}
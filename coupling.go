@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/report"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// runCoupling implements the coupling subcommand: files that tend to change together.
+func runCoupling(args []string) int {
+	fs := flag.NewFlagSet("coupling", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	var (
+		maxOriginal = fs.Float64("max-originality", 100, "Only pair files that are at most this percent original (analyzes the repository)")
+		reportFile  = fs.String("report", "", "Take originality from a saved report (see analyze --save-report) instead of analyzing")
+		minShared   = fs.Int("min-shared", 3, "Only report pairs that changed together in at least N commits")
+		maxFiles    = fs.Int("max-files", 30, "Skip commits that change more than N files, such as mass reformatting")
+		limit       = fs.Int("limit", 20, "Show at most N pairs (0 = all)")
+		format      = fs.String("format", "text", "Output format: text, markdown, csv or json")
+		outputPath  = fs.String("output", "", "Write the report to this file instead of stdout (non-text formats)")
+	)
+	scope := addHistoryFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage:
+  ship-of-theseus coupling [options]
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Finds files that tend to change in the same commits, which points at hidden
+  dependencies: modules that keep getting rewritten together. For each pair of
+  files it reports the shared commits, the degree of coupling (shared commits as
+  a percentage of the average of both files' commits) and the confidence in each
+  direction (how many of one file's commits also changed the other).
+
+  Only files that exist at the end of the selected history are paired. With
+  --max-originality, only files that are at most that original are; this needs
+  their originality, which is measured by analyzing the repository unless
+  --report is given.
+
+  By default commits on every ref are counted. --branch counts one branch instead,
+  and --first-parent only its mainline, with merges standing for their branches.
+  --since and --until count only the commits made within the window.
+
+Examples:
+  ship-of-theseus coupling
+  ship-of-theseus coupling --max-originality 50 --since 2024-01-01
+  ship-of-theseus coupling --min-shared 5 --format csv --output coupling.csv
+`)
+	}
+
+	fs.Parse(args)
+
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	filter, err := scope.filter()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	switch *format {
+	case "text", "markdown", "csv", "json":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: --format must be one of: text, markdown, csv, json\n")
+		return 1
+	}
+
+	if *outputPath != "" && *format == "text" {
+		fmt.Fprintf(os.Stderr, "Error: --output requires a file format such as --format markdown\n")
+		return 1
+	}
+
+	switch {
+	case *maxOriginal < 0 || *maxOriginal > 100:
+		err = fmt.Errorf("--max-originality must be between 0 and 100")
+	case *minShared < 1:
+		err = fmt.Errorf("--min-shared must be at least 1")
+	case *maxFiles < 2:
+		err = fmt.Errorf("--max-files must be at least 2")
+	case *limit < 0:
+		err = fmt.Errorf("--limit cannot be negative")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Status messages go to stderr when stdout carries a machine-readable report
+	status := os.Stdout
+	if *format != "text" {
+		status = os.Stderr
+	}
+
+	fmt.Fprintf(status, "🚢 Ship of Theseus v%s\n", version)
+
+	// The files to pair: those with low enough originality when it is needed or at
+	// hand, otherwise every file that exists
+	var r *report.Report
+	var originality map[string]float64
+	var keep func(path string) bool
+	if *maxOriginal < 100 || *reportFile != "" {
+		if r, err = fileReport(absPath, *reportFile, scope, filter, common.workers, status); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		originality = make(map[string]float64, len(r.Files))
+		for _, f := range r.Files {
+			if f.TotalLines > 0 {
+				originality[f.Path] = float64(f.OriginalLines) / float64(f.TotalLines) * 100.0
+			}
+		}
+		keep = func(path string) bool {
+			pct, ok := originality[path]
+			return ok && pct <= *maxOriginal
+		}
+	} else {
+		files, err := currentFiles(absPath, scope, filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		keep = func(path string) bool { return files[path] }
+	}
+
+	co, err := analyzer.GetCoChanges(absPath, filter, *maxFiles, keep)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not read commit history: %v\n", err)
+		return 1
+	}
+
+	couplings := report.NewCouplings(co, *minShared, originality)
+	if r != nil {
+		couplings.CommitHash = r.CommitHash
+	}
+
+	var render func(w io.Writer) error
+	switch *format {
+	case "markdown":
+		render = func(w io.Writer) error { return visualizer.WriteCouplingsMarkdown(w, couplings, *limit) }
+	case "csv":
+		render = func(w io.Writer) error { return visualizer.WriteCouplingsCSV(w, couplings, *limit) }
+	case "json":
+		if *limit > 0 && *limit < len(couplings.Pairs) {
+			couplings.Pairs = couplings.Pairs[:*limit]
+		}
+		render = func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(couplings)
+		}
+	default:
+		visualizer.DisplayCouplings(couplings, *limit)
+		return 0
+	}
+
+	if *outputPath == "" {
+		err = render(os.Stdout)
+	} else {
+		err = writeFile(*outputPath, render)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not write %s report: %v\n", *format, err)
+		return 1
+	}
+	if *outputPath != "" {
+		fmt.Fprintf(status, "Coupling report written to %s\n", *outputPath)
+	}
+	return 0
+}
+
+// currentFiles lists the files an analysis would cover at the end of the selected
+// history, without analyzing them.
+func currentFiles(repoPath string, scope *historyFlags, filter analyzer.CommitFilter) (map[string]bool, error) {
+	analysisPath, removeCheckout, err := scope.checkout(repoPath, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer removeCheckout()
+
+	files, err := analyzer.ListFilesToAnalyze(analysisPath)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool, len(files))
+	for _, file := range files {
+		paths[file] = true
+	}
+	return paths, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/report"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// runDiff implements the diff subcommand: originality compared between two points.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	limit := fs.Int("limit", 20, "Show at most N changed files (0 = all)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage:
+  ship-of-theseus diff [options] <base> [<head>]
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Compares originality between two points. Each of <base> and <head> is either a
+  report file (see analyze --save-report) or a git ref, which is analyzed in a
+  temporary worktree. Without <head>, HEAD is analyzed (uncommitted changes are
+  ignored).
+
+Examples:
+  ship-of-theseus diff origin/main
+  ship-of-theseus diff v1.0.0 v2.0.0
+  ship-of-theseus diff baseline.json
+`)
+	}
+
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return 2
+	}
+
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	base, err := loadOrAnalyze(absPath, fs.Arg(0), common.workers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var head *report.Report
+	if fs.NArg() == 2 {
+		head, err = loadOrAnalyze(absPath, fs.Arg(1), common.workers)
+	} else {
+		fmt.Printf("Analyzing HEAD...\n\n")
+		head, err = analyzeReport(absPath, common.workers)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Println()
+	fmt.Printf("Base: %s\n", describeReport(base))
+	fmt.Printf("Head: %s\n\n", describeReport(head))
+
+	visualizer.DisplayDiff(report.Compare(base, head), *limit)
+	return 0
+}
+
+// loadOrAnalyze returns the report for spec: a report file if one exists at that
+// path, otherwise the analysis of spec as a git ref.
+func loadOrAnalyze(repoPath, spec string, numWorkers int) (*report.Report, error) {
+	if info, err := os.Stat(spec); err == nil && !info.IsDir() {
+		r, err := report.Load(spec)
+		if err != nil {
+			return nil, fmt.Errorf("Could not load report: %v", err)
+		}
+		return r, nil
+	}
+
+	fmt.Printf("Analyzing %s...\n\n", spec)
+	var r *report.Report
+	err := analyzer.WithWorktree(repoPath, spec, func(worktree string) error {
+		var err error
+		r, err = analyzeReport(worktree, numWorkers)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Could not analyze %s: %v", spec, err)
+	}
+	return r, nil
+}
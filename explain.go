@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// runExplain implements the explain subcommand: how one line's score was reached.
+func runExplain(args []string) int {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	common := addPathFlag(fs)
+	originOpts := addOriginFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage:
+  ship-of-theseus explain [options] <path>:<line>
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Shows each step of a line's classification: the file's first commit, every line
+  of the original version within the search window with its similarity, the
+  threshold, and whether the line counts as original.
+
+Example:
+  ship-of-theseus explain internal/auth/session.go:42
+`)
+	}
+
+	positional := parseArgs(fs, args)
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	idx := strings.LastIndex(positional[0], ":")
+	lineNum, err := strconv.Atoi(positional[0][idx+1:])
+	if idx <= 0 || err != nil {
+		fmt.Fprintf(os.Stderr, "Error: expected <path>:<line>, got %q\n", positional[0])
+		return 2
+	}
+
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	filePath, err := repoRelativePath(absPath, positional[0][:idx])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	origin, err := originOpts.resolve(absPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	explanation, err := analyzer.ExplainLine(absPath, filePath, lineNum, origin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	visualizer.DisplayExplanation(explanation)
+	return 0
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/models"
+	"ship-of-theseus/internal/tui"
+)
+
+// runExplore implements the explore subcommand: the interactive terminal explorer.
+func runExplore(args []string) int {
+	fs := flag.NewFlagSet("explore", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	var (
+		sampleRate = fs.Int("sample", 50, "Sample every Nth commit for history timeline")
+		memBudget  = fs.Int("memory-budget", 512, "MB of line-level detail to keep in memory before spilling to a temp file (0 = unlimited)")
+	)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage:
+  ship-of-theseus explore [options]
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Analyzes the repository, then opens a keyboard-driven explorer: browse the
+  directory rollup, sort by originality, similarity, size or name, open a file to
+  see each line colored by similarity, and step through the timeline.
+  Press ? inside the explorer for the key bindings.
+`)
+	}
+
+	fs.Parse(args)
+
+	// Fail before analyzing, which can take minutes, when there is no terminal to explore in
+	if err := tui.CheckTerminal(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *sampleRate < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --sample must be at least 1\n")
+		return 1
+	}
+
+	if *memBudget < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --memory-budget cannot be negative\n")
+		return 1
+	}
+
+	fmt.Printf("🚢 Ship of Theseus v%s\n", version)
+	fmt.Printf("Analyzing repository: %s\n\n", absPath)
+
+	// Lines beyond the budget are read back from the spool when a file is opened
+	spool := analyzer.NewLineSpool(int64(*memBudget) * 1024 * 1024)
+	defer spool.Remove()
+
+	analysis, err := analyzer.AnalyzeRepositoryWithOptions(absPath, analyzer.Options{
+		NumWorkers: common.workers,
+		Sinks:      []analyzer.ResultSink{spool},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nError during analysis: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("Generating historical timeline...")
+	if err := analyzer.AddSnapshotsToAnalysis(analysis, absPath, analyzer.Sampling{Every: *sampleRate}, analyzer.CommitFilter{}); err != nil {
+		fmt.Printf("Warning: Could not generate historical timeline: %v\n", err)
+	}
+
+	err = tui.Run(analysis, func(file *models.FileAnalysis) ([]*models.LineHistory, error) {
+		lines, _, err := spool.Lines(file)
+		return lines, err
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/filter"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// runFile implements the file subcommand: a line-by-line drilldown of one file.
+func runFile(args []string) int {
+	fs := flag.NewFlagSet("file", flag.ExitOnError)
+	common := addPathFlag(fs)
+	originOpts := addOriginFlags(fs)
+	colorMode := fs.String("color", "auto", "Color lines by originality tier: auto, always or never")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage:
+  ship-of-theseus file [options] <path>
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Analyzes a single file as committed at HEAD and prints every code line with its
+  similarity to the file's first version, the original line it was matched to,
+  and the commits that introduced and last modified it.
+
+Examples:
+  ship-of-theseus file internal/legacy/billing.go
+  ship-of-theseus file --color never main.go | less
+`)
+	}
+
+	positional := parseArgs(fs, args)
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	color, err := colorEnabled(*colorMode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	filePath, err := repoRelativePath(absPath, positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if filter.ShouldSkipFile(filePath) {
+		fmt.Fprintf(os.Stderr, "Warning: %s is excluded from repository analysis (binary, generated or vendored)\n", filePath)
+	}
+
+	origin, err := originOpts.resolve(absPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	file, err := analyzer.AnalyzeFile(absPath, filePath, origin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not analyze %s: %v\n", filePath, err)
+		return 1
+	}
+
+	visualizer.DisplayFile(file, color)
+	return 0
+}
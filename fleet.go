@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/report"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// pathList is a flag that can be given several times.
+type pathList []string
+
+func (p *pathList) String() string {
+	return strings.Join(*p, ", ")
+}
+
+func (p *pathList) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+// runFleet implements the fleet subcommand: one combined report for many repositories.
+func runFleet(args []string) int {
+	fs := flag.NewFlagSet("fleet", flag.ExitOnError)
+	var paths pathList
+	fs.Var(&paths, "path", "Path to a git repository (repeat for each repository)")
+	var (
+		manifest   = fs.String("manifest", "", "File listing repository paths, one per line")
+		numWorkers = fs.Int("workers", analyzer.GetDefaultWorkerCount(), "Number of parallel workers, shared by all repositories")
+		format     = fs.String("format", "text", "Output format: text, markdown, csv or json")
+		outputPath = fs.String("output", "", "Write the report to this file instead of stdout (non-text formats)")
+	)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage:
+  ship-of-theseus fleet --path <repo> --path <repo> ... [options]
+  ship-of-theseus fleet --manifest <file> [options]
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Analyzes several repositories with one shared worker pool and reports each
+  repository's originality alongside totals for the whole fleet. Fleet totals
+  are weighted by lines of code, so large repositories count for more. A
+  repository that can't be analyzed is reported and left out; the command
+  fails only when none can be.
+
+  A manifest lists one repository per line; blank lines and lines starting
+  with # are ignored, and relative paths are resolved from the manifest's
+  directory. --path and --manifest can be combined.
+
+Examples:
+  ship-of-theseus fleet --path ../billing --path ../accounts
+  ship-of-theseus fleet --manifest services.txt --format markdown --output FLEET.md
+  ship-of-theseus fleet --manifest services.txt --format json --output fleet.json
+`)
+	}
+
+	fs.Parse(args)
+
+	if *numWorkers < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --workers must be at least 1\n")
+		return 1
+	}
+
+	switch *format {
+	case "text", "markdown", "csv", "json":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: --format must be one of: text, markdown, csv, json\n")
+		return 1
+	}
+
+	if *outputPath != "" && *format == "text" {
+		fmt.Fprintf(os.Stderr, "Error: --output requires a file format such as --format markdown\n")
+		return 1
+	}
+
+	if *manifest != "" {
+		listed, err := readManifest(*manifest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not read manifest: %v\n", err)
+			return 1
+		}
+		paths = append(paths, listed...)
+	}
+
+	if len(paths) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no repositories given; use --path (repeatable) or --manifest\n")
+		return 1
+	}
+
+	repoPaths, err := resolveRepoPaths(paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Status messages go to stderr when stdout carries a machine-readable report
+	status := os.Stdout
+	if *format != "text" {
+		status = os.Stderr
+	}
+
+	fmt.Fprintf(status, "🚢 Ship of Theseus v%s\n", version)
+	fmt.Fprintf(status, "Analyzing %d repositories\n", len(repoPaths))
+	fmt.Fprintf(status, "Workers: %d (shared)\n\n", *numWorkers)
+
+	analyses, err := analyzer.AnalyzeRepositories(repoPaths, analyzer.Options{
+		NumWorkers:        *numWorkers,
+		DropLineHistories: true, // Fleet reports summarize per file at most
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nError during analysis: %v\n", err)
+		return 1
+	}
+
+	// Repositories that couldn't be analyzed were reported and have no analysis
+	names := repoNames(repoPaths)
+	var repos []report.FleetRepo
+	for i, repoPath := range repoPaths {
+		if analyses[i] == nil {
+			continue
+		}
+		repos = append(repos, report.FleetRepo{
+			Name:   names[i],
+			Path:   repoPath,
+			Report: newReport(repoPath, analyses[i]),
+		})
+	}
+	fleet := report.NewFleet(repos)
+
+	var render func(w io.Writer) error
+	switch *format {
+	case "markdown":
+		render = func(w io.Writer) error { return visualizer.WriteFleetMarkdown(w, fleet) }
+	case "csv":
+		render = func(w io.Writer) error { return visualizer.WriteFleetCSV(w, fleet) }
+	case "json":
+		render = func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(fleet)
+		}
+	default:
+		visualizer.DisplayFleet(fleet)
+		return 0
+	}
+
+	if *outputPath == "" {
+		err = render(os.Stdout)
+	} else {
+		err = writeFile(*outputPath, render)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not write %s report: %v\n", *format, err)
+		return 1
+	}
+	if *outputPath != "" {
+		fmt.Fprintf(status, "Fleet report written to %s\n", *outputPath)
+	}
+	return 0
+}
+
+// readManifest reads repository paths from a manifest file. Relative paths are
+// resolved from the manifest's directory.
+func readManifest(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(filepath.Dir(path), line)
+		}
+		paths = append(paths, line)
+	}
+	return paths, scanner.Err()
+}
+
+// resolveRepoPaths resolves each path to a repository, dropping repeats.
+func resolveRepoPaths(paths []string) ([]string, error) {
+	seen := make(map[string]bool, len(paths))
+	var repoPaths []string
+	for _, path := range paths {
+		absPath, err := resolveRepoPath(path)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[absPath] {
+			seen[absPath] = true
+			repoPaths = append(repoPaths, absPath)
+		}
+	}
+	return repoPaths, nil
+}
+
+// repoNames names each repository after its directory, falling back to the full
+// path for repositories whose directories share a name.
+func repoNames(repoPaths []string) []string {
+	count := make(map[string]int, len(repoPaths))
+	for _, repoPath := range repoPaths {
+		count[filepath.Base(repoPath)]++
+	}
+
+	names := make([]string, len(repoPaths))
+	for i, repoPath := range repoPaths {
+		names[i] = filepath.Base(repoPath)
+		if count[names[i]] > 1 {
+			names[i] = repoPath
+		}
+	}
+	return names
+}
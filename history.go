@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"ship-of-theseus/internal/report"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// runHistory implements the history subcommand: a table of recorded runs.
+func runHistory(args []string) int {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	common := addPathFlag(fs)
+	historyFile := fs.String("history-file", "", "History store (default: <path>/"+report.DefaultHistoryPath+")")
+	limit := fs.Int("limit", 0, "Show only the N most recent runs (0 = all)")
+	fs.Parse(args)
+
+	reports, err := loadHistory(common.path, *historyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *limit > 0 && len(reports) > *limit {
+		reports = reports[len(reports)-*limit:]
+	}
+
+	visualizer.DisplayHistory(reports)
+	return 0
+}
+
+// runTrend implements the trend subcommand: a timeline of measured originality.
+func runTrend(args []string) int {
+	fs := flag.NewFlagSet("trend", flag.ExitOnError)
+	common := addPathFlag(fs)
+	historyFile := fs.String("history-file", "", "History store (default: <path>/"+report.DefaultHistoryPath+")")
+	fs.Parse(args)
+
+	reports, err := loadHistory(common.path, *historyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	visualizer.DisplayTrend(reports)
+	return 0
+}
+
+// openHistory resolves the history store for a repository, honoring an explicit override.
+func openHistory(repoPath, historyFile string) (*report.History, error) {
+	if historyFile != "" {
+		return report.OpenHistory(historyFile), nil
+	}
+
+	absPath, err := resolveRepoPath(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return report.OpenHistory(filepath.Join(absPath, report.DefaultHistoryPath)), nil
+}
+
+// loadHistory opens and reads the history store for a repository.
+func loadHistory(repoPath, historyFile string) ([]*report.Report, error) {
+	history, err := openHistory(repoPath, historyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := history.Load()
+	if err != nil {
+		return nil, fmt.Errorf("Could not read history: %v", err)
+	}
+	return reports, nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/report"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// runHotspots implements the hotspots subcommand: files ranked by churn and rewriting.
+func runHotspots(args []string) int {
+	fs := flag.NewFlagSet("hotspots", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	var (
+		reportFile = fs.String("report", "", "Take originality from a saved report (see analyze --save-report) instead of analyzing")
+		limit      = fs.Int("limit", 20, "Show at most N files (0 = all)")
+		format     = fs.String("format", "text", "Output format: text, markdown, csv or json")
+		outputPath = fs.String("output", "", "Write the report to this file instead of stdout (non-text formats)")
+	)
+	scope := addHistoryFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage:
+  ship-of-theseus hotspots [options]
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Ranks files that are both heavily churned and largely rewritten. Churn is how
+  many commits changed each file and how many lines they added and removed (from
+  git log --numstat, following renames); it is combined with each file's current
+  originality and size. A file scores high only when it changes often and little
+  of its original code is left, which makes it a candidate for refactoring or
+  better tests.
+
+  By default churn is counted over every ref. --branch counts one branch instead,
+  and --first-parent only its mainline, with merges standing for their branches.
+  --since and --until count only the commits made within the window. Originality
+  is measured at the end of the selected history, unless --report is given.
+
+Examples:
+  ship-of-theseus hotspots
+  ship-of-theseus hotspots --since 2024-01-01 --limit 10
+  ship-of-theseus hotspots --report baseline.json --format csv --output hotspots.csv
+`)
+	}
+
+	fs.Parse(args)
+
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	filter, err := scope.filter()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	switch *format {
+	case "text", "markdown", "csv", "json":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: --format must be one of: text, markdown, csv, json\n")
+		return 1
+	}
+
+	if *outputPath != "" && *format == "text" {
+		fmt.Fprintf(os.Stderr, "Error: --output requires a file format such as --format markdown\n")
+		return 1
+	}
+
+	if *limit < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --limit cannot be negative\n")
+		return 1
+	}
+
+	// Status messages go to stderr when stdout carries a machine-readable report
+	status := os.Stdout
+	if *format != "text" {
+		status = os.Stderr
+	}
+
+	fmt.Fprintf(status, "🚢 Ship of Theseus v%s\n", version)
+	r, err := fileReport(absPath, *reportFile, scope, filter, common.workers, status)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	churn, commits, err := analyzer.GetFileChurn(absPath, filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not read commit history: %v\n", err)
+		return 1
+	}
+	hotspots := report.NewHotspots(r, churn, commits)
+
+	var render func(w io.Writer) error
+	switch *format {
+	case "markdown":
+		render = func(w io.Writer) error { return visualizer.WriteHotspotsMarkdown(w, hotspots, *limit) }
+	case "csv":
+		render = func(w io.Writer) error { return visualizer.WriteHotspotsCSV(w, hotspots, *limit) }
+	case "json":
+		if *limit > 0 && *limit < len(hotspots.Files) {
+			hotspots.Files = hotspots.Files[:*limit]
+		}
+		render = func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(hotspots)
+		}
+	default:
+		visualizer.DisplayHotspots(hotspots, *limit)
+		return 0
+	}
+
+	if *outputPath == "" {
+		err = render(os.Stdout)
+	} else {
+		err = writeFile(*outputPath, render)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not write %s report: %v\n", *format, err)
+		return 1
+	}
+	if *outputPath != "" {
+		fmt.Fprintf(status, "Hotspots written to %s\n", *outputPath)
+	}
+	return 0
+}
+
+// fileReport returns a report with per-file summaries: reportFile when given, or else
+// the analysis of the code at the end of the selected history.
+func fileReport(repoPath, reportFile string, scope *historyFlags, filter analyzer.CommitFilter, numWorkers int, status io.Writer) (*report.Report, error) {
+	if reportFile != "" {
+		r, err := report.Load(reportFile)
+		if err != nil {
+			return nil, fmt.Errorf("Could not load report: %v", err)
+		}
+		if len(r.Files) == 0 {
+			return nil, fmt.Errorf("report %s has no per-file summaries", reportFile)
+		}
+		return r, nil
+	}
+
+	analysisPath, removeCheckout, err := scope.checkout(repoPath, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer removeCheckout()
+
+	fmt.Fprintf(status, "Analyzing repository: %s\n\n", repoPath)
+	analysis, err := analyzer.AnalyzeRepositoryWithOptions(analysisPath, analyzer.Options{
+		NumWorkers:        numWorkers,
+		Origin:            analyzer.Origin{FirstParent: filter.FirstParent},
+		DropLineHistories: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %v", err)
+	}
+	fmt.Fprintln(status)
+
+	return newReport(analysisPath, analysis), nil
+}
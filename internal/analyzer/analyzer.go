@@ -16,6 +16,23 @@ import (
 	"github.com/schollz/progressbar/v3"
 )
 
+// Options configures a repository analysis.
+type Options struct {
+	NumWorkers int // Number of parallel workers (use runtime.NumCPU() for default)
+
+	// Origin is what lines are compared against; the zero value is each file's first commit.
+	Origin Origin
+
+	// Sinks receive each file's results as soon as it finishes, in the order given,
+	// before the built-in Aggregator computes the totals. Exporters that need line
+	// detail should come before a LineSpool, which may release it.
+	Sinks []ResultSink
+
+	// DropLineHistories releases each file's line-level detail after the sinks have seen it.
+	// Aggregate totals are unaffected; use it when lines are streamed elsewhere.
+	DropLineHistories bool
+}
+
 // AnalyzeRepository performs a complete Ship of Theseus analysis on a git repository.
 // It processes files in parallel and returns aggregated statistics about code originality.
 //
@@ -26,6 +43,108 @@ import (
 // Returns:
 //   - CodebaseAnalysis with complete metrics and per-file breakdowns
 func AnalyzeRepository(repoPath string, numWorkers int) (*models.CodebaseAnalysis, error) {
+	return AnalyzeRepositoryWithOptions(repoPath, Options{NumWorkers: numWorkers})
+}
+
+// AnalyzeRepositoryWithOptions is AnalyzeRepository with a configurable result pipeline.
+// Workers emit each file's results to opts.Sinks as soon as it completes and then to an
+// Aggregator, so exporters can write incrementally and a LineSpool can keep line-level
+// detail within a memory budget instead of holding every LineHistory until the end.
+func AnalyzeRepositoryWithOptions(repoPath string, opts Options) (*models.CodebaseAnalysis, error) {
+	numWorkers := opts.NumWorkers
+	if numWorkers < 1 {
+		numWorkers = GetDefaultWorkerCount()
+	}
+
+	filesToAnalyze, err := listFilesToAnalyze(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(os.Stderr, "Analyzing %d files with %d workers...\n\n", len(filesToAnalyze), numWorkers)
+
+	// Shuffle files to distribute slow files evenly across workers
+	// This prevents clustering of slow files at the end causing the progress bar to "hang"
+	jobs := jobsFor(0, filesToAnalyze)
+	shuffleJobs(jobs)
+
+	// Process files in parallel using worker pool, aggregating as results arrive
+	aggregator := NewAggregator()
+	if err := processFilesParallel([]string{repoPath}, jobs, numWorkers, opts.Origin, [][]ResultSink{opts.sinks(aggregator)}); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintln(os.Stderr) // Add space after progress bar
+
+	analysis := aggregator.Analysis()
+	analysis.Origin = opts.Origin.String()
+	return analysis, nil
+}
+
+// AnalyzeRepositories analyzes several repositories with one shared worker pool, so
+// workers move on to the next repository's files instead of idling while the last
+// files of a small repository finish. Results are returned in the order of repoPaths.
+// A repository whose files can't be listed (e.g. a path that isn't a git repository)
+// is reported on stderr and left out, with a nil result; it is an error only when no
+// repository remains. opts.Sinks aren't supported, since file paths are only unique
+// within a repository.
+func AnalyzeRepositories(repoPaths []string, opts Options) ([]*models.CodebaseAnalysis, error) {
+	if len(opts.Sinks) > 0 {
+		return nil, fmt.Errorf("result sinks are not supported when analyzing several repositories")
+	}
+
+	numWorkers := opts.NumWorkers
+	if numWorkers < 1 {
+		numWorkers = GetDefaultWorkerCount()
+	}
+
+	var jobs []fileJob
+	aggregators := make([]*Aggregator, len(repoPaths))
+	sinks := make([][]ResultSink, len(repoPaths))
+	remaining := 0
+	for i, repoPath := range repoPaths {
+		filesToAnalyze, err := listFilesToAnalyze(repoPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Skipping %s: %v\n", repoPath, err)
+			continue
+		}
+		jobs = append(jobs, jobsFor(i, filesToAnalyze)...)
+		aggregators[i] = NewAggregator()
+		sinks[i] = opts.sinks(aggregators[i])
+		remaining++
+	}
+	if remaining == 0 {
+		return nil, fmt.Errorf("none of the %d repositories could be analyzed", len(repoPaths))
+	}
+
+	fmt.Fprintf(os.Stderr, "Analyzing %d files in %d repositories with %d workers...\n\n", len(jobs), remaining, numWorkers)
+
+	shuffleJobs(jobs)
+	if err := processFilesParallel(repoPaths, jobs, numWorkers, opts.Origin, sinks); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintln(os.Stderr) // Add space after progress bar
+
+	analyses := make([]*models.CodebaseAnalysis, len(repoPaths))
+	for i, aggregator := range aggregators {
+		if aggregator == nil {
+			continue
+		}
+		analyses[i] = aggregator.Analysis()
+		analyses[i].Origin = opts.Origin.String()
+	}
+	return analyses, nil
+}
+
+// ListFilesToAnalyze lists the files of a repository that an analysis covers: those
+// tracked by git, minus binary, generated and vendored files.
+func ListFilesToAnalyze(repoPath string) ([]string, error) {
+	return listFilesToAnalyze(repoPath)
+}
+
+// listFilesToAnalyze validates a repository and lists its files that should be analyzed.
+func listFilesToAnalyze(repoPath string) ([]string, error) {
 	// Validate repository exists
 	if _, err := os.Stat(filepath.Join(repoPath, ".git")); os.IsNotExist(err) {
 		return nil, fmt.Errorf("not a git repository: %s", repoPath)
@@ -49,21 +168,20 @@ func AnalyzeRepository(repoPath string, numWorkers int) (*models.CodebaseAnalysi
 		return nil, fmt.Errorf("no files to analyze after filtering")
 	}
 
-	fmt.Printf("Analyzing %d files with %d workers...\n\n", len(filesToAnalyze), numWorkers)
-
-	// Shuffle files to distribute slow files evenly across workers
-	// This prevents clustering of slow files at the end causing the progress bar to "hang"
-	shuffleFiles(filesToAnalyze)
-
-	// Process files in parallel using worker pool
-	fileAnalyses := processFilesParallel(repoPath, filesToAnalyze, numWorkers)
-
-	fmt.Println() // Add space after progress bar
-
-	// Aggregate results
-	analysis := aggregateResults(fileAnalyses)
+	return filesToAnalyze, nil
+}
 
-	return analysis, nil
+// sinks returns the sink chain for an analysis: the caller's sinks, then the
+// optional line-detail drop, then the aggregator.
+func (opts Options) sinks(aggregator *Aggregator) []ResultSink {
+	sinks := append([]ResultSink{}, opts.Sinks...)
+	if opts.DropLineHistories {
+		sinks = append(sinks, SinkFunc(func(file *models.FileAnalysis) error {
+			file.LineHistories = nil
+			return nil
+		}))
+	}
+	return append(sinks, aggregator)
 }
 
 // getGitTrackedFiles gets all files tracked by git (respects .gitignore automatically).
@@ -84,14 +202,36 @@ func getGitTrackedFiles(repoPath string) ([]string, error) {
 	return lines, nil
 }
 
-// shuffleFiles randomizes the order of files to distribute slow files evenly.
+// fileJob is one unit of work for the pool: a file, and the index of the repository
+// it belongs to.
+type fileJob struct {
+	repo int
+	path string
+}
+
+// fileResult is a finished file and the index of its repository.
+type fileResult struct {
+	repo int
+	file *models.FileAnalysis
+}
+
+// jobsFor creates the jobs for files of one repository.
+func jobsFor(repo int, files []string) []fileJob {
+	jobs := make([]fileJob, len(files))
+	for i, file := range files {
+		jobs[i] = fileJob{repo: repo, path: file}
+	}
+	return jobs
+}
+
+// shuffleJobs randomizes the order of files to distribute slow files evenly.
 // This prevents the progress bar from appearing to "hang" at 99% when slow files
 // cluster at the end of the alphabetically-sorted git ls-files output.
-func shuffleFiles(files []string) {
+func shuffleJobs(jobs []fileJob) {
 	// Seed with current time for randomness
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	r.Shuffle(len(files), func(i, j int) {
-		files[i], files[j] = files[j], files[i]
+	r.Shuffle(len(jobs), func(i, j int) {
+		jobs[i], jobs[j] = jobs[j], jobs[i]
 	})
 }
 
@@ -103,16 +243,21 @@ type FileProgress struct {
 
 // processFilesParallel processes files using a worker pool for parallelization.
 // This is critical for performance on large repositories.
-func processFilesParallel(repoPath string, files []string, numWorkers int) []*models.FileAnalysis {
+//
+// Each job names its repository by index into repoPaths, and its results go to that
+// repository's sink chain. Results are handed to the sinks as they arrive rather than
+// collected first. The result channel is deliberately small: if the sinks fall behind,
+// workers block instead of piling up finished analyses in memory.
+func processFilesParallel(repoPaths []string, jobs []fileJob, numWorkers int, origin Origin, sinks [][]ResultSink) error {
 	// Create channels for work distribution
-	workChan := make(chan string, len(files))
-	resultChan := make(chan *models.FileAnalysis, len(files))
+	workChan := make(chan fileJob, len(jobs))
+	resultChan := make(chan fileResult, numWorkers*2)
 
 	// Track current file being processed
 	progress := &FileProgress{}
 
 	// Create progress bar
-	bar := progressbar.NewOptions(len(files),
+	bar := progressbar.NewOptions(len(jobs),
 		progressbar.OptionSetDescription("Analyzing files"),
 		progressbar.OptionSetWriter(os.Stderr),
 		progressbar.OptionShowCount(),
@@ -134,12 +279,12 @@ func processFilesParallel(repoPath string, files []string, numWorkers int) []*mo
 	// Start workers
 	for i := 0; i < numWorkers; i++ {
 		wg.Add(1)
-		go workerWithProgress(repoPath, workChan, resultChan, progress, bar, &wg)
+		go workerWithProgress(repoPaths, origin, workChan, resultChan, progress, bar, &wg)
 	}
 
 	// Send work to workers
-	for _, file := range files {
-		workChan <- file
+	for _, job := range jobs {
+		workChan <- job
 	}
 	close(workChan)
 
@@ -149,35 +294,57 @@ func processFilesParallel(repoPath string, files []string, numWorkers int) []*mo
 		close(resultChan)
 	}()
 
-	// Collect results
-	var results []*models.FileAnalysis
+	// Dispatch results to the sinks
+	var sinkErr error
 	for result := range resultChan {
-		if result != nil {
-			results = append(results, result)
+		if sinkErr != nil {
+			// Keep draining so workers can finish after a sink failure
+			continue
+		}
+
+		for _, sink := range sinks[result.repo] {
+			if err := sink.Consume(result.file); err != nil {
+				sinkErr = fmt.Errorf("failed to handle results for %s: %w", result.file.Path, err)
+				break
+			}
 		}
 	}
 
-	return results
+	for _, chain := range sinks {
+		for _, sink := range chain {
+			if err := sink.Close(); err != nil && sinkErr == nil {
+				sinkErr = err
+			}
+		}
+	}
+
+	return sinkErr
 }
 
 // workerWithProgress processes files from the work channel and sends results to result channel.
 // Updates progress bar as files complete.
-func workerWithProgress(repoPath string, workChan <-chan string, resultChan chan<- *models.FileAnalysis, progress *FileProgress, bar *progressbar.ProgressBar, wg *sync.WaitGroup) {
+func workerWithProgress(repoPaths []string, origin Origin, workChan <-chan fileJob, resultChan chan<- fileResult, progress *FileProgress, bar *progressbar.ProgressBar, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	for filePath := range workChan {
+	for job := range workChan {
+		// Name the repository too when there are several
+		filePath := job.path
+		if len(repoPaths) > 1 {
+			filePath = filepath.Join(filepath.Base(repoPaths[job.repo]), job.path)
+		}
+
 		// Update current file being processed
 		progress.Lock()
 		progress.currentFile = filePath
 		bar.Describe(fmt.Sprintf("Analyzing: %s", truncatePath(filePath, 60)))
 		progress.Unlock()
 
-		analysis, err := analyzeFile(repoPath, filePath)
+		analysis, err := AnalyzeFile(repoPaths[job.repo], job.path, origin)
 		if err != nil {
 			// Log error but continue processing other files (clear line first)
 			fmt.Fprintf(os.Stderr, "\nWarning: Failed to analyze %s: %v\n", filePath, err)
 		} else {
-			resultChan <- analysis
+			resultChan <- fileResult{repo: job.repo, file: analysis}
 		}
 
 		// Update progress bar
@@ -194,8 +361,10 @@ func truncatePath(path string, maxLen int) string {
 	return "..." + path[len(path)-maxLen+3:]
 }
 
-// analyzeFile performs a complete analysis of a single file.
-func analyzeFile(repoPath, filePath string) (*models.FileAnalysis, error) {
+// AnalyzeFile performs a complete analysis of a single file as committed at HEAD.
+// filePath is relative to the repository root, as listed by git ls-files; its lines
+// are compared with the file's version at origin.
+func AnalyzeFile(repoPath, filePath string, origin Origin) (*models.FileAnalysis, error) {
 	// Read committed file content from git (not working directory)
 	// This ensures blame line count matches file line count
 	content, err := GetFileAtCommit(repoPath, "HEAD", filePath)
@@ -221,7 +390,7 @@ func analyzeFile(repoPath, filePath string) (*models.FileAnalysis, error) {
 	}
 
 	// Trace line histories
-	histories, err := TraceFileLines(repoPath, filePath, content)
+	histories, err := TraceFileLines(repoPath, filePath, content, origin)
 	if err != nil {
 		return nil, fmt.Errorf("failed to trace lines: %w", err)
 	}
@@ -252,32 +421,6 @@ func analyzeFile(repoPath, filePath string) (*models.FileAnalysis, error) {
 	}, nil
 }
 
-// aggregateResults combines individual file analyses into repository-wide statistics.
-func aggregateResults(fileAnalyses []*models.FileAnalysis) *models.CodebaseAnalysis {
-	totalLines := 0
-	originalLines := 0
-	totalSimilarity := 0.0
-
-	for _, fa := range fileAnalyses {
-		totalLines += fa.TotalLines
-		originalLines += fa.OriginalLines
-		totalSimilarity += fa.AvgSimilarity * float64(fa.TotalLines)
-	}
-
-	avgSimilarity := 0.0
-	if totalLines > 0 {
-		avgSimilarity = totalSimilarity / float64(totalLines)
-	}
-
-	return &models.CodebaseAnalysis{
-		TotalLines:          totalLines,
-		OriginalLines:       originalLines,
-		AverageSimilarity:   avgSimilarity,
-		FileAnalyses:        fileAnalyses,
-		HistoricalSnapshots: []models.Snapshot{}, // Will be filled by snapshot generation
-	}
-}
-
 // GetDefaultWorkerCount returns the recommended number of workers (number of CPUs).
 func GetDefaultWorkerCount() int {
 	return runtime.NumCPU()
@@ -0,0 +1,28 @@
+package analyzer
+
+import "testing"
+
+func TestAnalyzeRepositoriesSkipsFailingRepositories(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.write("main.go", "package main\n\nfunc main() {}\n")
+	repo.commit("Add main.go")
+	notARepo := t.TempDir()
+
+	analyses, err := AnalyzeRepositories([]string{notARepo, repo.dir}, Options{NumWorkers: 1})
+	if err != nil {
+		t.Fatalf("AnalyzeRepositories failed: %v", err)
+	}
+	if len(analyses) != 2 {
+		t.Fatalf("got %d results, want one per repository", len(analyses))
+	}
+	if analyses[0] != nil {
+		t.Errorf("result for a directory that isn't a repository = %+v, want nil", analyses[0])
+	}
+	if analyses[1] == nil || len(analyses[1].FileAnalyses) != 1 || analyses[1].FileAnalyses[0].Path != "main.go" {
+		t.Errorf("result for the repository = %+v, want main.go analyzed", analyses[1])
+	}
+
+	if _, err := AnalyzeRepositories([]string{notARepo, t.TempDir()}, Options{NumWorkers: 1}); err == nil {
+		t.Error("AnalyzeRepositories with no analyzable repository succeeded, want an error")
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"ship-of-theseus/internal/filter"
 )
 
 // BlameInfo represents blame information for a single line in a file.
@@ -85,11 +87,17 @@ func parseBlameOutput(output []byte) ([]BlameInfo, error) {
 	return result, nil
 }
 
-// GetFileHistory retrieves the commit history for a file, following renames.
+// GetFileHistory retrieves the commit history for a file, following renames. With
+// firstParent, only the first parent of merge commits is followed, so a file added on
+// a merged branch appears in the merge commit rather than the branch's commits.
 // Returns a list of commit hashes in reverse chronological order (newest first).
-func GetFileHistory(repoPath, filePath string) ([]string, error) {
-	// Run: git -C <repo> log --follow --pretty=format:%H -- <file>
-	cmd := exec.Command("git", "-C", repoPath, "log", "--follow", "--pretty=format:%H", "--", filePath)
+func GetFileHistory(repoPath, filePath string, firstParent bool) ([]string, error) {
+	// Run: git -C <repo> log --follow [--first-parent] --pretty=format:%H -- <file>
+	args := []string{"-C", repoPath, "log", "--follow", "--pretty=format:%H"}
+	if firstParent {
+		args = append(args, "--first-parent")
+	}
+	cmd := exec.Command("git", append(args, "--", filePath)...)
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("git log failed for %s: %w", filePath, err)
@@ -118,10 +126,12 @@ func GetFileAtCommit(repoPath, commitHash, filePath string) (string, error) {
 }
 
 // GetCommitStats retrieves statistics about a commit (additions, deletions).
+// Merge commits are diffed against their first parent, so a merge counts everything
+// the merged branch brought in, once.
 // Returns a map with keys: "additions" and "deletions" as integers.
 func GetCommitStats(repoPath, commitHash string) (map[string]int, error) {
-	// Run: git -C <repo> show --stat --pretty=format: <commit>
-	cmd := exec.Command("git", "-C", repoPath, "show", "--stat", "--pretty=format:", commitHash)
+	// Run: git -C <repo> show -m --first-parent --stat --pretty=format: <commit>
+	cmd := exec.Command("git", "-C", repoPath, "show", "-m", "--first-parent", "--stat", "--pretty=format:", commitHash)
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("git show --stat failed for %s: %w", commitHash, err)
@@ -130,6 +140,71 @@ func GetCommitStats(repoPath, commitHash string) (map[string]int, error) {
 	return parseCommitStats(output)
 }
 
+// GetDiffStats counts the lines added and removed between two commits (or trees) in
+// the files that are analyzed, skipping binary, generated and vendored files.
+func GetDiffStats(repoPath, from, to string) (added, removed int, err error) {
+	// Run: git -C <repo> diff --numstat --no-renames <from> <to>
+	cmd := exec.Command("git", "-C", repoPath, "diff", "--numstat", "--no-renames", from, to)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("git diff --numstat failed for %s..%s: %w", from, to, err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		// Lines look like "<added>\t<removed>\t<path>"; binary files show "-" counts
+		fields := strings.SplitN(scanner.Text(), "\t", 3)
+		if len(fields) != 3 || filter.ShouldSkipFile(fields[2]) {
+			continue
+		}
+		a, errA := strconv.Atoi(fields[0])
+		r, errR := strconv.Atoi(fields[1])
+		if errA != nil || errR != nil {
+			continue
+		}
+		added += a
+		removed += r
+	}
+
+	return added, removed, scanner.Err()
+}
+
+// GetEmptyTree returns the hash of the empty tree, which commits can be diffed
+// against to count everything they contain.
+func GetEmptyTree(repoPath string) (string, error) {
+	// Run: git -C <repo> hash-object -t tree --stdin < /dev/null
+	cmd := exec.Command("git", "-C", repoPath, "hash-object", "-t", "tree", "--stdin")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git hash-object failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetAuthors returns the email addresses (after .mailmap) of the authors of the commits
+// reachable from to but not from from, or of all commits reachable from to when from
+// is empty. Addresses may repeat.
+func GetAuthors(repoPath, from, to string) ([]string, error) {
+	// Run: git -C <repo> log --pretty=format:%aE <to> [^<from>] --
+	args := []string{"-C", repoPath, "log", "--pretty=format:%aE", to}
+	if from != "" {
+		args = append(args, "^"+from)
+	}
+	cmd := exec.Command("git", append(args, "--")...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed for authors of %s: %w", to, err)
+	}
+
+	var authors []string
+	for _, author := range strings.Split(string(output), "\n") {
+		if author = strings.TrimSpace(author); author != "" {
+			authors = append(authors, author)
+		}
+	}
+	return authors, nil
+}
+
 // parseCommitStats extracts addition/deletion counts from git show --stat output.
 // Example line: " 3 files changed, 45 insertions(+), 12 deletions(-)"
 func parseCommitStats(output []byte) (map[string]int, error) {
@@ -171,11 +246,48 @@ func parseCommitStats(output []byte) (map[string]int, error) {
 	return stats, nil
 }
 
-// GetAllCommits retrieves all commits in the repository in reverse chronological order.
+// CommitFilter restricts which commits make up the history. The zero value is every
+// commit reachable from any ref.
+type CommitFilter struct {
+	Since       time.Time // Only commits made at or after this; zero for no lower bound
+	Until       time.Time // Only commits made at or before this; zero for no upper bound
+	Branch      string    // Only commits reachable from this ref; every ref when empty
+	FirstParent bool      // Only follow the first parent of merge commits (the mainline)
+}
+
+// args returns the git log arguments that select the filtered commits.
+func (f CommitFilter) args() []string {
+	var args []string
+	switch {
+	case f.Branch != "":
+		args = append(args, f.Branch)
+	case f.FirstParent:
+		// Following the mainline of every ref at once would mix them up again
+		args = append(args, "HEAD")
+	default:
+		// Notes refs (see NotesRef) point at bookkeeping commits, not project history
+		args = append(args, "--exclude=refs/notes/*", "--all")
+	}
+
+	if f.FirstParent {
+		args = append(args, "--first-parent")
+	}
+	if !f.Since.IsZero() {
+		args = append(args, "--since="+f.Since.Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		args = append(args, "--until="+f.Until.Format(time.RFC3339))
+	}
+	return args
+}
+
+// GetAllCommits retrieves all commits in the repository that pass the filter, in
+// reverse chronological order.
 // Returns commit hashes with their timestamps.
-func GetAllCommits(repoPath string) ([]CommitInfo, error) {
-	// Run: git -C <repo> log --all --pretty=format:%H|%ct
-	cmd := exec.Command("git", "-C", repoPath, "log", "--all", "--pretty=format:%H|%ct")
+func GetAllCommits(repoPath string, filter CommitFilter) ([]CommitInfo, error) {
+	// Run: git -C <repo> log --pretty=format:%H|%ct <filter args> --
+	args := append([]string{"-C", repoPath, "log", "--pretty=format:%H|%ct"}, filter.args()...)
+	cmd := exec.Command("git", append(args, "--")...)
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("git log failed: %w", err)
@@ -209,3 +321,30 @@ type CommitInfo struct {
 	Hash string
 	Date time.Time
 }
+
+// GetHeadCommit returns the hash and commit date of HEAD.
+func GetHeadCommit(repoPath string) (CommitInfo, error) {
+	return GetCommit(repoPath, "HEAD")
+}
+
+// GetCommit returns the hash and commit date of the commit ref points at.
+func GetCommit(repoPath, ref string) (CommitInfo, error) {
+	// Run: git -C <repo> log -1 --pretty=format:%H|%ct <ref> --
+	cmd := exec.Command("git", "-C", repoPath, "log", "-1", "--pretty=format:%H|%ct", ref, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("git log failed for %s: %w", ref, err)
+	}
+
+	parts := strings.Split(strings.TrimSpace(string(output)), "|")
+	if len(parts) != 2 {
+		return CommitInfo{}, fmt.Errorf("unexpected git log output: %q", output)
+	}
+
+	timestamp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("invalid commit timestamp %q: %w", parts[1], err)
+	}
+
+	return CommitInfo{Hash: parts[0], Date: time.Unix(timestamp, 0)}, nil
+}
@@ -0,0 +1,211 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ship-of-theseus/internal/filter"
+	"ship-of-theseus/internal/models"
+)
+
+// FileChange is the lines one commit added to and removed from one file.
+type FileChange struct {
+	Path    string // The file's current path, following later renames
+	Added   int
+	Removed int
+}
+
+// CommitChanges lists the files a commit changed.
+type CommitChanges struct {
+	Hash  string
+	Files []FileChange
+}
+
+// GetFileChanges lists the files changed by each commit that passes the filter, newest
+// first. Like GetCommitStats, but per file: paths are reported by the name each file
+// has now, so a renamed file's history stays together, and files the analysis skips
+// are left out. Merges are left out too, as their changes are counted in the commits
+// they merge, except when following the first parent, where a merge stands for its
+// branch and counts as the diff against its first parent.
+func GetFileChanges(repoPath string, f CommitFilter) ([]CommitChanges, error) {
+	// Run: git -C <repo> log -z -M --numstat [--diff-merges=first-parent] --pretty=format:%H <filter args> --
+	args := []string{"-C", repoPath, "log", "-z", "-M", "--numstat", "--pretty=format:%H"}
+	if f.FirstParent {
+		args = append(args, "--diff-merges=first-parent")
+	}
+	args = append(args, f.args()...)
+	cmd := exec.Command("git", append(args, "--")...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log --numstat failed: %w", err)
+	}
+
+	return parseFileChanges(output), nil
+}
+
+// GetChurn counts the lines added and removed by the commits reachable from to but not
+// from from, summed commit by commit, so a line rewritten twice counts twice and
+// edits that later commits undo still count. Files the analysis skips are left out,
+// and merges are handled as in GetFileChanges.
+func GetChurn(repoPath, from, to string, firstParent bool) (added, removed int, err error) {
+	// Run: git -C <repo> log -z -M --numstat --pretty=format:%H [--first-parent --diff-merges=first-parent] <from>..<to> --
+	args := []string{"-C", repoPath, "log", "-z", "-M", "--numstat", "--pretty=format:%H"}
+	if firstParent {
+		args = append(args, "--first-parent", "--diff-merges=first-parent")
+	}
+	cmd := exec.Command("git", append(args, from+".."+to, "--")...)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("git log --numstat failed for %s..%s: %w", from, to, err)
+	}
+
+	for _, commit := range parseFileChanges(output) {
+		for _, file := range commit.Files {
+			added += file.Added
+			removed += file.Removed
+		}
+	}
+	return added, removed, nil
+}
+
+// parseFileChanges parses git log -z --numstat output. Each commit starts with its
+// hash and a newline, followed by "<added>\t<removed>\t<path>" entries separated by
+// NULs; a rename has an empty path followed by the old and new paths as entries of
+// their own. Walking from newest to oldest, renames map old paths to current ones.
+func parseFileChanges(output []byte) []CommitChanges {
+	renamed := make(map[string]string)
+	current := func(path string) string {
+		if name, ok := renamed[path]; ok {
+			return name
+		}
+		return path
+	}
+
+	var commits []CommitChanges
+	tokens := strings.Split(string(bytes.TrimRight(output, "\x00")), "\x00")
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token == "" {
+			continue
+		}
+
+		// A commit header, possibly followed by its first entry
+		if hash, rest, _ := strings.Cut(token, "\n"); isHash(hash) {
+			commits = append(commits, CommitChanges{Hash: hash})
+			if rest == "" {
+				continue
+			}
+			token = rest
+		}
+		if len(commits) == 0 {
+			continue
+		}
+
+		fields := strings.SplitN(token, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		path := fields[2]
+		if path == "" && i+2 < len(tokens) {
+			// Renamed: history before this commit belongs to the file's current name
+			from, to := tokens[i+1], tokens[i+2]
+			i += 2
+			path = current(to)
+			renamed[from] = path
+		} else {
+			path = current(path)
+		}
+		if filter.ShouldSkipFile(path) {
+			continue
+		}
+
+		// Binary files show "-" counts; the commit still changed them
+		added, _ := strconv.Atoi(fields[0])
+		removed, _ := strconv.Atoi(fields[1])
+		c := &commits[len(commits)-1]
+		c.Files = append(c.Files, FileChange{Path: path, Added: added, Removed: removed})
+	}
+
+	return commits
+}
+
+// isHash reports whether s is a full hexadecimal commit hash: SHA-1, or SHA-256 in
+// repositories that use it.
+func isHash(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return len(s) == 40 || len(s) == 64
+}
+
+// GetFileChurn totals GetFileChanges per file: how many commits changed each file and
+// how many lines they added and removed, keyed by the file's current path. It also
+// returns the number of commits walked.
+func GetFileChurn(repoPath string, f CommitFilter) (map[string]models.FileChurn, int, error) {
+	commits, err := GetFileChanges(repoPath, f)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	churn := make(map[string]models.FileChurn)
+	for _, commit := range commits {
+		for _, change := range commit.Files {
+			c := churn[change.Path]
+			c.Commits++
+			c.LinesAdded += change.Added
+			c.LinesRemoved += change.Removed
+			churn[change.Path] = c
+		}
+	}
+	return churn, len(commits), nil
+}
+
+// GetCoChanges counts, for the commits that pass the filter, how often each file
+// changed and how often each pair of files changed in the same commit. Only files
+// keep accepts are counted. Commits changing more than maxFiles of them are skipped:
+// mass reformatting or renaming would otherwise couple everything with everything.
+func GetCoChanges(repoPath string, f CommitFilter, maxFiles int, keep func(path string) bool) (*models.CoChanges, error) {
+	commits, err := GetFileChanges(repoPath, f)
+	if err != nil {
+		return nil, err
+	}
+
+	co := &models.CoChanges{
+		Files: make(map[string]int),
+		Pairs: make(map[models.FilePair]int),
+	}
+	for _, commit := range commits {
+		var paths []string
+		seen := make(map[string]bool, len(commit.Files))
+		for _, change := range commit.Files {
+			if keep(change.Path) && !seen[change.Path] {
+				seen[change.Path] = true
+				paths = append(paths, change.Path)
+			}
+		}
+		if len(paths) == 0 {
+			continue
+		}
+		if len(paths) > maxFiles {
+			co.Skipped++
+			continue
+		}
+
+		co.Commits++
+		sort.Strings(paths)
+		for i, a := range paths {
+			co.Files[a]++
+			for _, b := range paths[i+1:] {
+				co.Pairs[models.FilePair{A: a, B: b}]++
+			}
+		}
+	}
+	return co, nil
+}
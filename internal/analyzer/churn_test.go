@@ -0,0 +1,181 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFileChanges(t *testing.T) {
+	sha1 := func(c byte) string { return strings.Repeat(string(c), 40) }
+	sha256 := func(c byte) string { return strings.Repeat(string(c), 64) }
+
+	tests := []struct {
+		name   string
+		output string
+		want   []CommitChanges
+	}{
+		{
+			name:   "empty",
+			output: "",
+			want:   nil,
+		},
+		{
+			name:   "one commit",
+			output: sha1('a') + "\n3\t1\tmain.go\x00" + "10\t0\tutil.go\x00",
+			want: []CommitChanges{
+				{Hash: sha1('a'), Files: []FileChange{{"main.go", 3, 1}, {"util.go", 10, 0}}},
+			},
+		},
+		{
+			name:   "commits are separated by an empty entry",
+			output: sha1('b') + "\n1\t0\ta.go\x00\x00" + sha1('a') + "\n5\t0\ta.go\x00",
+			want: []CommitChanges{
+				{Hash: sha1('b'), Files: []FileChange{{"a.go", 1, 0}}},
+				{Hash: sha1('a'), Files: []FileChange{{"a.go", 5, 0}}},
+			},
+		},
+		{
+			name:   "commit without changes",
+			output: sha1('c') + "\x00" + sha1('b') + "\n1\t1\ta.go\x00",
+			want: []CommitChanges{
+				{Hash: sha1('c')},
+				{Hash: sha1('b'), Files: []FileChange{{"a.go", 1, 1}}},
+			},
+		},
+		{
+			name: "renames map older paths to the current name",
+			output: sha1('c') + "\n2\t0\tnew.go\x00\x00" +
+				sha1('b') + "\n1\t1\t\x00old.go\x00new.go\x00\x00" +
+				sha1('a') + "\n7\t0\told.go\x00",
+			want: []CommitChanges{
+				{Hash: sha1('c'), Files: []FileChange{{"new.go", 2, 0}}},
+				{Hash: sha1('b'), Files: []FileChange{{"new.go", 1, 1}}},
+				{Hash: sha1('a'), Files: []FileChange{{"new.go", 7, 0}}},
+			},
+		},
+		{
+			name: "chained renames",
+			output: sha1('c') + "\n0\t0\t\x00b.go\x00c.go\x00\x00" +
+				sha1('b') + "\n0\t0\t\x00a.go\x00b.go\x00\x00" +
+				sha1('a') + "\n4\t0\ta.go\x00",
+			want: []CommitChanges{
+				{Hash: sha1('c'), Files: []FileChange{{"c.go", 0, 0}}},
+				{Hash: sha1('b'), Files: []FileChange{{"c.go", 0, 0}}},
+				{Hash: sha1('a'), Files: []FileChange{{"c.go", 4, 0}}},
+			},
+		},
+		{
+			name:   "binary files count as changed with no lines",
+			output: sha1('a') + "\n-\t-\tdata.blob\x00",
+			want: []CommitChanges{
+				{Hash: sha1('a'), Files: []FileChange{{"data.blob", 0, 0}}},
+			},
+		},
+		{
+			name:   "skipped files are left out",
+			output: sha1('a') + "\n1\t0\tlogo.png\x00" + "1\t0\tnode_modules/x.js\x00" + "1\t0\tmain.go\x00",
+			want: []CommitChanges{
+				{Hash: sha1('a'), Files: []FileChange{{"main.go", 1, 0}}},
+			},
+		},
+		{
+			name:   "SHA-256 hashes",
+			output: sha256('e') + "\n2\t2\tmain.go\x00\x00" + sha256('d') + "\n1\t0\tmain.go\x00",
+			want: []CommitChanges{
+				{Hash: sha256('e'), Files: []FileChange{{"main.go", 2, 2}}},
+				{Hash: sha256('d'), Files: []FileChange{{"main.go", 1, 0}}},
+			},
+		},
+		{
+			name:   "entries before any commit are ignored",
+			output: "1\t0\tstray.go\x00" + sha1('a') + "\n1\t0\ta.go\x00",
+			want: []CommitChanges{
+				{Hash: sha1('a'), Files: []FileChange{{"a.go", 1, 0}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseFileChanges([]byte(tt.output))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFileChanges() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsHash(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{strings.Repeat("0123456789", 4), true},
+		{strings.Repeat("abcdef0123456789", 4), true},
+		{strings.Repeat("a", 39), false},
+		{strings.Repeat("a", 41), false},
+		{strings.Repeat("A", 40), false},
+		{strings.Repeat("g", 40), false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isHash(tt.s); got != tt.want {
+			t.Errorf("isHash(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestGetChurn(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.write("a.go", "one\ntwo\nthree\n")
+	start := repo.commit("Add a.go")
+
+	// An edit and its revert change nothing in the end, but are two commits of work
+	repo.write("a.go", "one\nTWO\nthree\n")
+	repo.write("node_modules/dep.js", "skipped\n")
+	repo.commit("Edit a.go")
+	repo.write("a.go", "one\ntwo\nthree\n")
+	reverted := repo.commit("Revert the edit")
+
+	// A branch of two commits, merged
+	repo.git("checkout", "-q", "-b", "feature")
+	repo.write("b.go", "b\n")
+	repo.commit("Add b.go")
+	repo.write("b.go", "B\nbb\n")
+	repo.commit("Extend b.go")
+	repo.git("checkout", "-q", "main")
+	repo.days++
+	repo.git("merge", "-q", "--no-ff", "-m", "Merge feature", "feature")
+	merge := repo.git("rev-parse", "HEAD")
+
+	tests := []struct {
+		name                   string
+		from, to               string
+		firstParent            bool
+		wantAdded, wantRemoved int
+	}{
+		{"edits and reverts both count", start, reverted, false, 2, 2},
+		{"no commits in between", reverted, reverted, false, 0, 0},
+		{"branch commits count one by one", reverted, merge, false, 3, 1},
+		{"first parent counts the merge's diff", reverted, merge, true, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed, err := GetChurn(repo.dir, tt.from, tt.to, tt.firstParent)
+			if err != nil {
+				t.Fatalf("GetChurn failed: %v", err)
+			}
+			if added != tt.wantAdded || removed != tt.wantRemoved {
+				t.Errorf("GetChurn() = +%d/-%d, want +%d/-%d", added, removed, tt.wantAdded, tt.wantRemoved)
+			}
+		})
+	}
+
+	// The net diff, by contrast, sees no change at all
+	if added, removed, err := GetDiffStats(repo.dir, start, reverted); err != nil || added != 0 || removed != 0 {
+		t.Errorf("GetDiffStats() = +%d/-%d, %v, want +0/-0", added, removed, err)
+	}
+}
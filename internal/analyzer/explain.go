@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"fmt"
+	"ship-of-theseus/internal/models"
+	"strings"
+)
+
+// ExplainLine traces one line of a file at HEAD the same way the analysis does, and
+// records every intermediate step: the file history, the commit chosen as the
+// origin, each candidate in the search window with its similarity, and the verdict.
+//
+// The verdict comes from the same tracing code as the analysis, so the explanation
+// can never disagree with it; the candidates are recomputed for display.
+func ExplainLine(repoPath, filePath string, lineNum int, origin Origin) (*models.LineExplanation, error) {
+	content, err := GetFileAtCommit(repoPath, "HEAD", filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file from git: %w", err)
+	}
+
+	blameInfos, err := GetBlame(repoPath, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(content, "\n")
+	if lineNum < 1 || lineNum > len(blameInfos) || lineNum > len(lines) {
+		return nil, fmt.Errorf("%s has %d lines; line %d does not exist", filePath, len(blameInfos), lineNum)
+	}
+
+	currentLine := lines[lineNum-1]
+	if strings.TrimSpace(currentLine) == "" {
+		return nil, fmt.Errorf("line %d of %s is blank; blank lines are not analyzed", lineNum, filePath)
+	}
+
+	fileOrigin := origin.resolve(repoPath, filePath)
+	result := traceLine(fileOrigin, currentLine, lineNum, blameInfos[lineNum-1])
+
+	explanation := &models.LineExplanation{
+		FilePath:       filePath,
+		LineNum:        lineNum,
+		OriginName:     origin.String(),
+		FileCommits:    fileOrigin.Commits,
+		FirstCommit:    fileOrigin.Commit,
+		WindowRadius:   LineMovementWindow,
+		Threshold:      MinimumSimilarityThreshold,
+		Result:         result,
+		Original:       IsOriginal(result.Similarity),
+		FallbackReason: fileOrigin.Fallback,
+	}
+	if fileOrigin.Lines == nil {
+		return explanation, nil
+	}
+
+	start, end := searchWindow(len(fileOrigin.Lines), lineNum)
+	explanation.WindowStart, explanation.WindowEnd = start+1, end+1
+
+	for i := start; i <= end; i++ {
+		explanation.Candidates = append(explanation.Candidates, models.Candidate{
+			LineNum:    i + 1,
+			Line:       fileOrigin.Lines[i],
+			Similarity: CalculateSimilarity(currentLine, fileOrigin.Lines[i]),
+		})
+	}
+
+	return explanation, nil
+}
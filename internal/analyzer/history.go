@@ -23,68 +23,51 @@ const (
 //
 // Returns a LineHistory struct with first/last commit info and similarity score.
 func TraceLineHistory(repoPath, filePath string, currentLine string, currentLineNum int, blameInfo BlameInfo) (*models.LineHistory, error) {
-	// Get the complete file history following renames
-	commitHashes, err := GetFileHistory(repoPath, filePath)
-	if err != nil || len(commitHashes) == 0 {
-		// If we can't get history, use blame info as both first and last
-		return &models.LineHistory{
-			CurrentLine:     currentLine,
-			OriginalLine:    currentLine,
-			CurrentLineNum:  currentLineNum,
-			OriginalLineNum: currentLineNum,
-			FirstCommitHash: blameInfo.CommitHash,
-			FirstCommitDate: blameInfo.CommitDate,
-			LastCommitHash:  blameInfo.CommitHash,
-			LastCommitDate:  blameInfo.CommitDate,
-			Similarity:      1.0,
-		}, nil
-	}
-
-	// Get the FIRST (oldest) commit where this file existed
-	firstCommitHash := commitHashes[len(commitHashes)-1]
+	return traceLine(Origin{}.resolve(repoPath, filePath), currentLine, currentLineNum, blameInfo), nil
+}
 
-	// Get file content at first commit
-	firstContent, err := GetFileAtCommit(repoPath, firstCommitHash, filePath)
-	if err != nil {
-		// File didn't exist in first commit - treat current as original
-		return &models.LineHistory{
-			CurrentLine:     currentLine,
-			OriginalLine:    currentLine,
-			CurrentLineNum:  currentLineNum,
-			OriginalLineNum: currentLineNum,
-			FirstCommitHash: firstCommitHash,
-			FirstCommitDate: blameInfo.CommitDate,
-			LastCommitHash:  blameInfo.CommitHash,
-			LastCommitDate:  blameInfo.CommitDate,
-			Similarity:      1.0,
-		}, nil
+// traceLine compares a line with its file's origin, steps 2-4 of TraceLineHistory.
+func traceLine(origin fileOrigin, currentLine string, currentLineNum int, blameInfo BlameInfo) *models.LineHistory {
+	history := &models.LineHistory{
+		CurrentLine:     currentLine,
+		CurrentLineNum:  currentLineNum,
+		FirstCommitHash: origin.Commit,
+		FirstCommitDate: blameInfo.CommitDate, // Simplified: use blame date
+		LastCommitHash:  blameInfo.CommitHash,
+		LastCommitDate:  blameInfo.CommitDate,
 	}
 
-	firstLines := strings.Split(firstContent, "\n")
+	switch {
+	case origin.New:
+		// The file didn't exist at the origin, so the line first appeared in its own commit
+		history.OriginalLineNum = currentLineNum
+		history.FirstCommitHash = blameInfo.CommitHash
+		history.Similarity = 0.0
+		return history
+
+	case origin.Lines == nil:
+		// If we can't get history, treat the current line as original
+		history.OriginalLine = currentLine
+		history.OriginalLineNum = currentLineNum
+		if origin.Commit == "" {
+			history.FirstCommitHash = blameInfo.CommitHash
+		}
+		history.Similarity = 1.0
+		return history
+	}
 
-	// Look for a similar line in the first commit within ±10 lines of current position
-	originalLine, originalLineNum := findSimilarLineInRange(firstLines, currentLineNum, currentLine)
+	// Look for a similar line in the origin within ±10 lines of current position
+	originalLine, originalLineNum := findSimilarLineInRange(origin.Lines, currentLineNum, currentLine)
 
 	if originalLine == "" {
-		// No similar line found in first commit - this is a new line
-		originalLine = ""
+		// No similar line found in the origin - this is a new line
 		originalLineNum = currentLineNum
 	}
 
-	// Calculate similarity between first and current
-	similarity := CalculateSimilarity(originalLine, currentLine)
-
-	return &models.LineHistory{
-		CurrentLine:     currentLine,
-		OriginalLine:    originalLine,
-		CurrentLineNum:  currentLineNum,
-		OriginalLineNum: originalLineNum,
-		FirstCommitHash: firstCommitHash,
-		FirstCommitDate: blameInfo.CommitDate, // Simplified: use blame date
-		LastCommitHash:  blameInfo.CommitHash,
-		LastCommitDate:  blameInfo.CommitDate,
-		Similarity:      similarity,
-	}, nil
+	history.OriginalLine = originalLine
+	history.OriginalLineNum = originalLineNum
+	history.Similarity = CalculateSimilarity(originalLine, currentLine)
+	return history
 }
 
 // findSimilarLineInRange searches for a line similar to targetLine within a ±10 line window.
@@ -97,19 +80,8 @@ func findSimilarLineInRange(lines []string, targetLineNum int, targetLine string
 		return "", 0
 	}
 
-	// Convert to 0-indexed for array access
-	targetIdx := targetLineNum - 1
-
-	// Calculate search range (±10 lines)
-	start := targetIdx - LineMovementWindow
-	if start < 0 {
-		start = 0
-	}
-
-	end := targetIdx + LineMovementWindow
-	if end >= len(lines) {
-		end = len(lines) - 1
-	}
+	// Calculate search range (±10 lines), 0-indexed for array access
+	start, end := searchWindow(len(lines), targetLineNum)
 
 	// Find the best match within range
 	bestMatch := ""
@@ -131,9 +103,28 @@ func findSimilarLineInRange(lines []string, targetLineNum int, targetLine string
 	return bestMatch, bestLineNum
 }
 
-// TraceFileLines analyzes all non-comment, non-blank lines in a file.
+// searchWindow returns the 0-indexed, inclusive range of lines that
+// findSimilarLineInRange scans for a line currently at targetLineNum.
+func searchWindow(numLines, targetLineNum int) (int, int) {
+	targetIdx := targetLineNum - 1
+
+	start := targetIdx - LineMovementWindow
+	if start < 0 {
+		start = 0
+	}
+
+	end := targetIdx + LineMovementWindow
+	if end >= numLines {
+		end = numLines - 1
+	}
+
+	return start, end
+}
+
+// TraceFileLines analyzes all non-comment, non-blank lines in a file, comparing
+// each with the file's version at origin.
 // Returns a slice of LineHistory for each analyzed line.
-func TraceFileLines(repoPath, filePath, fileContent string) ([]*models.LineHistory, error) {
+func TraceFileLines(repoPath, filePath, fileContent string, origin Origin) ([]*models.LineHistory, error) {
 	// Get blame information for the file
 	blameInfos, err := GetBlame(repoPath, filePath)
 	if err != nil {
@@ -153,6 +144,9 @@ func TraceFileLines(repoPath, filePath, fileContent string) ([]*models.LineHisto
 	// Only process lines that have blame info
 	lines = lines[:len(blameInfos)]
 
+	// Every line of the file is compared with the same origin
+	fileOrigin := origin.resolve(repoPath, filePath)
+
 	var histories []*models.LineHistory
 
 	// Trace each line's history
@@ -166,15 +160,8 @@ func TraceFileLines(repoPath, filePath, fileContent string) ([]*models.LineHisto
 		blameInfo := blameInfos[i]
 		lineNum := i + 1 // 1-indexed
 
-		// Trace this line back through history
-		history, err := TraceLineHistory(repoPath, filePath, line, lineNum, blameInfo)
-		if err != nil {
-			// Log warning but continue with other lines
-			// In production, you'd want proper logging here
-			continue
-		}
-
-		histories = append(histories, history)
+		// Trace this line back to its origin
+		histories = append(histories, traceLine(fileOrigin, line, lineNum, blameInfo))
 	}
 
 	return histories, nil
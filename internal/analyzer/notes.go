@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// NotesRef is the git notes ref that analysis summaries are stored under.
+const NotesRef = "refs/notes/theseus"
+
+// AddNote attaches content to a commit under NotesRef, replacing any existing note.
+// Notes travel with the repository (git push origin refs/notes/theseus) without
+// adding files to the tree.
+func AddNote(repoPath, commitHash, content string) error {
+	// Run: git -C <repo> notes --ref <ref> add -f -F - <commit>
+	cmd := exec.Command("git", "-C", repoPath, "notes", "--ref", NotesRef, "add", "-f", "-F", "-", commitHash)
+	cmd.Stdin = strings.NewReader(content)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git notes add failed for %s: %s", commitHash, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// GetNote returns the note attached to a commit under NotesRef.
+func GetNote(repoPath, commitHash string) (string, error) {
+	// Run: git -C <repo> notes --ref <ref> show <commit>
+	cmd := exec.Command("git", "-C", repoPath, "notes", "--ref", NotesRef, "show", commitHash)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no %s note for %s", NotesRef, commitHash)
+	}
+	return string(output), nil
+}
+
+// GetAllNotes returns every note under NotesRef, keyed by annotated commit hash.
+// Note blobs are read in a single git cat-file --batch call.
+func GetAllNotes(repoPath string) (map[string]string, error) {
+	// Run: git -C <repo> notes --ref <ref> list
+	cmd := exec.Command("git", "-C", repoPath, "notes", "--ref", NotesRef, "list")
+	output, err := cmd.Output()
+	if err != nil {
+		// A repository without the notes ref simply has no notes
+		return map[string]string{}, nil
+	}
+
+	// Each line is: <note-blob> <annotated-commit>
+	var blobs, commits []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) == 2 {
+			blobs = append(blobs, parts[0])
+			commits = append(commits, parts[1])
+		}
+	}
+
+	notes := make(map[string]string, len(blobs))
+	if len(blobs) == 0 {
+		return notes, nil
+	}
+
+	// Run: git -C <repo> cat-file --batch, feeding blob hashes on stdin
+	batch := exec.Command("git", "-C", repoPath, "cat-file", "--batch")
+	batch.Stdin = strings.NewReader(strings.Join(blobs, "\n") + "\n")
+	content, err := batch.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file failed: %w", err)
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(content))
+	for i := range blobs {
+		// Header: <hash> <type> <size>
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("unexpected end of git cat-file output")
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git cat-file header: %q", header)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid size in git cat-file header: %q", header)
+		}
+
+		body := make([]byte, size+1) // Content is followed by a newline
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return nil, fmt.Errorf("truncated git cat-file output: %w", err)
+		}
+		notes[commits[i]] = string(body[:size])
+	}
+
+	return notes, nil
+}
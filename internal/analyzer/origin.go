@@ -0,0 +1,176 @@
+package analyzer
+
+import (
+	"fmt"
+	"maps"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Origin chooses the version of each file that its current lines are compared
+// against. The zero Origin is the default: each file's first commit.
+type Origin struct {
+	// Commit, when set, makes the codebase at this commit the origin of every file,
+	// such as the upstream of a fork or a release the code is measured against.
+	// Files that don't exist there count as new; build the Origin with CommitOrigin
+	// so that renamed files are found under their old path.
+	Commit string
+
+	// Name describes the origin in reports, e.g. "upstream/main"; Commit when empty.
+	Name string
+
+	// FirstCommitIfMissing compares files that don't exist at Commit with their first
+	// commit instead of counting them as new.
+	FirstCommitIfMissing bool
+
+	// FirstParent finds each file's first commit along the mainline only, so files
+	// from merged branches originate in their merge commit.
+	FirstParent bool
+
+	renames map[string]string // Current path → path at Commit, for files renamed since
+}
+
+// CommitOrigin makes the codebase at commit the origin. Files renamed between commit
+// and HEAD are compared with their old path, as detected by git diff.
+func CommitOrigin(repoPath, commit, name string) (Origin, error) {
+	// Run: git -C <repo> diff --name-status --find-renames <commit> HEAD
+	cmd := exec.Command("git", "-C", repoPath, "diff", "--name-status", "--find-renames", commit, "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return Origin{}, fmt.Errorf("git diff failed for %s..HEAD: %w", commit, err)
+	}
+
+	renames := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		// Renames look like "R095\t<old path>\t<new path>"
+		fields := strings.Split(line, "\t")
+		if len(fields) == 3 && strings.HasPrefix(fields[0], "R") {
+			renames[fields[2]] = fields[1]
+		}
+	}
+
+	return Origin{Commit: commit, Name: name, renames: renames}, nil
+}
+
+// String describes the origin for reports; it is empty for the default origin.
+func (o Origin) String() string {
+	if o.Name != "" {
+		return o.Name
+	}
+	return o.Commit
+}
+
+// Equal reports whether two origins compare every file with the same version, so
+// that results computed against one hold for the other.
+func (o Origin) Equal(other Origin) bool {
+	return o.Commit == other.Commit && o.Name == other.Name &&
+		o.FirstCommitIfMissing == other.FirstCommitIfMissing && o.FirstParent == other.FirstParent &&
+		maps.Equal(o.renames, other.renames)
+}
+
+// fileOrigin is the origin of one file, resolved once before its lines are traced.
+type fileOrigin struct {
+	Commit   string   // Commit whose version of the file is the origin
+	Commits  []string // File history, newest first (first-commit origins only)
+	Lines    []string // The file at Commit; nil when there is nothing to compare with
+	New      bool     // The file doesn't exist at the origin, so every line is new
+	Fallback string   // Why lines aren't compared with the origin, when they aren't
+}
+
+// SinceOrigin makes the first version of each file within the time window starting at
+// filter.Since its origin: its version as of Since, or its first commit if it was
+// created later. When HEAD has no commits before Since, that is every file's first
+// commit, the default origin.
+func SinceOrigin(repoPath string, filter CommitFilter) (Origin, error) {
+	commit, err := CommitBefore(repoPath, "HEAD", filter.Since.Add(-time.Second), filter.FirstParent)
+	if err != nil || commit == "" {
+		return Origin{FirstParent: filter.FirstParent}, err
+	}
+
+	origin, err := CommitOrigin(repoPath, commit, fmt.Sprintf("versions as of %s (%s)", filter.Since.Format("2006-01-02"), commit[:7]))
+	origin.FirstCommitIfMissing = true
+	origin.FirstParent = filter.FirstParent
+	return origin, err
+}
+
+// resolve finds the origin of one file.
+func (o Origin) resolve(repoPath, filePath string) fileOrigin {
+	if o.Commit != "" {
+		path := filePath
+		if old, ok := o.renames[filePath]; ok {
+			path = old
+		}
+
+		content, err := GetFileAtCommit(repoPath, o.Commit, path)
+		switch {
+		case err == nil:
+			return fileOrigin{Commit: o.Commit, Lines: strings.Split(content, "\n")}
+		case !o.FirstCommitIfMissing:
+			return fileOrigin{
+				Commit:   o.Commit,
+				New:      true,
+				Fallback: "The file does not exist at the origin, so every line is new",
+			}
+		}
+	}
+
+	// Get the complete file history following renames
+	commits, err := GetFileHistory(repoPath, filePath, o.FirstParent)
+	if err != nil || len(commits) == 0 {
+		return fileOrigin{Fallback: "No file history was found, so the line is its own origin"}
+	}
+
+	// The FIRST (oldest) commit where this file existed
+	first := commits[len(commits)-1]
+	content, err := GetFileAtCommit(repoPath, first, filePath)
+	if err != nil {
+		return fileOrigin{
+			Commit:   first,
+			Commits:  commits,
+			Fallback: "The file has another name in its first commit, so the line is its own origin",
+		}
+	}
+
+	return fileOrigin{Commit: first, Commits: commits, Lines: strings.Split(content, "\n")}
+}
+
+// ResolveCommit resolves a ref (branch, tag, hash, upstream/main...) to a commit hash.
+func ResolveCommit(repoPath, ref string) (string, error) {
+	// Run: git -C <repo> rev-parse --verify <ref>^{commit}
+	cmd := exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown commit %q", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CommitBefore returns the last commit in ref's history made at or before t, or an
+// empty string if there is none. With firstParent, only the mainline is considered.
+func CommitBefore(repoPath, ref string, t time.Time, firstParent bool) (string, error) {
+	// Run: git -C <repo> rev-list -1 [--first-parent] --before=<date> <ref> --
+	args := []string{"-C", repoPath, "rev-list", "-1", "--before=" + t.Format(time.RFC3339)}
+	if firstParent {
+		args = append(args, "--first-parent")
+	}
+	cmd := exec.Command("git", append(args, ref, "--")...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-list failed: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// MergeBase returns the best common ancestor of two commits, where a fork diverged
+// from its upstream.
+func MergeBase(repoPath, a, b string) (string, error) {
+	// Run: git -C <repo> merge-base <a> <b>
+	cmd := exec.Command("git", "-C", repoPath, "merge-base", a, b)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no common ancestor of %s and %s", a, b)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import "testing"
+
+func TestOriginEqual(t *testing.T) {
+	base := Origin{Commit: "abc", Name: "upstream/main (abc)", renames: map[string]string{"b.go": "a.go"}}
+
+	tests := []struct {
+		name  string
+		other Origin
+		want  bool
+	}{
+		{"same origin", Origin{Commit: "abc", Name: "upstream/main (abc)", renames: map[string]string{"b.go": "a.go"}}, true},
+		{"another commit", Origin{Commit: "def", Name: "upstream/main (abc)", renames: map[string]string{"b.go": "a.go"}}, false},
+		{"another name", Origin{Commit: "abc", Name: "v1.0 (abc)", renames: map[string]string{"b.go": "a.go"}}, false},
+		{"another rename", Origin{Commit: "abc", Name: "upstream/main (abc)", renames: map[string]string{"c.go": "a.go"}}, false},
+		{"no renames", Origin{Commit: "abc", Name: "upstream/main (abc)"}, false},
+		{"first parent", Origin{Commit: "abc", Name: "upstream/main (abc)", renames: map[string]string{"b.go": "a.go"}, FirstParent: true}, false},
+		{"the default origin", Origin{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base.Equal(tt.other); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !(Origin{}).Equal(Origin{renames: map[string]string{}}) {
+		t.Error("default origins with and without an empty rename map differ, want equal")
+	}
+}
+
+func TestCommitOriginFollowsHead(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.write("a.go", "package a\n\nfunc A() {}\n")
+	base := repo.commit("Add a.go")
+
+	repo.git("mv", "a.go", "b.go")
+	repo.commit("Rename a.go to b.go")
+
+	before, err := CommitOrigin(repo.dir, base, "base")
+	if err != nil {
+		t.Fatalf("CommitOrigin failed: %v", err)
+	}
+	if got := before.renames["b.go"]; got != "a.go" {
+		t.Errorf("renames[b.go] = %q, want a.go", got)
+	}
+
+	// A later commit renames the file again: the same commit is a different origin now
+	repo.git("mv", "b.go", "c.go")
+	repo.commit("Rename b.go to c.go")
+
+	after, err := CommitOrigin(repo.dir, base, "base")
+	if err != nil {
+		t.Fatalf("CommitOrigin failed: %v", err)
+	}
+	if after.Equal(before) {
+		t.Errorf("origin with renames %v equals the one with %v, want them to differ", after.renames, before.renames)
+	}
+	if got := after.renames["c.go"]; got != "a.go" {
+		t.Errorf("renames[c.go] = %q, want a.go", got)
+	}
+}
@@ -0,0 +1,321 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"ship-of-theseus/internal/models"
+	"sync"
+)
+
+// ResultSink receives per-file results as the worker pool completes them.
+// Sinks are always called from a single collecting goroutine, in completion order,
+// so implementations don't need their own locking for Consume.
+type ResultSink interface {
+	// Consume handles one file's results. Sinks run in order, and a sink may release
+	// data (such as LineHistories) that later sinks no longer need.
+	Consume(file *models.FileAnalysis) error
+
+	// Close is called once after the last file has been consumed.
+	Close() error
+}
+
+// SinkFunc adapts a plain function to a ResultSink with a no-op Close.
+type SinkFunc func(file *models.FileAnalysis) error
+
+// Consume calls f(file).
+func (f SinkFunc) Consume(file *models.FileAnalysis) error {
+	return f(file)
+}
+
+// Close does nothing.
+func (f SinkFunc) Close() error {
+	return nil
+}
+
+// Aggregator is the terminal sink of every analysis. It accumulates repository-wide
+// totals incrementally and keeps each file's summary, so the full set of results
+// never has to be materialized before aggregation.
+type Aggregator struct {
+	totalLines      int
+	originalLines   int
+	totalSimilarity float64
+	files           []*models.FileAnalysis
+}
+
+// NewAggregator creates an empty Aggregator.
+func NewAggregator() *Aggregator {
+	return &Aggregator{}
+}
+
+// Consume adds a file to the running totals.
+func (a *Aggregator) Consume(file *models.FileAnalysis) error {
+	a.totalLines += file.TotalLines
+	a.originalLines += file.OriginalLines
+	a.totalSimilarity += file.AvgSimilarity * float64(file.TotalLines)
+	a.files = append(a.files, file)
+	return nil
+}
+
+// Close does nothing; the Aggregator stays readable after the analysis finishes.
+func (a *Aggregator) Close() error {
+	return nil
+}
+
+// Analysis returns the aggregated results consumed so far.
+func (a *Aggregator) Analysis() *models.CodebaseAnalysis {
+	avgSimilarity := 0.0
+	if a.totalLines > 0 {
+		avgSimilarity = a.totalSimilarity / float64(a.totalLines)
+	}
+
+	return &models.CodebaseAnalysis{
+		TotalLines:          a.totalLines,
+		OriginalLines:       a.originalLines,
+		AverageSimilarity:   avgSimilarity,
+		FileAnalyses:        a.files,
+		HistoricalSnapshots: []models.Snapshot{}, // Will be filled by snapshot generation
+	}
+}
+
+// LineSpool bounds the memory held by line-level detail. Line histories stay in memory
+// until their estimated size exceeds the budget; after that, each file's lines are
+// appended to a temporary file and released from the FileAnalysis. Spilled lines can
+// be read back on demand with Lines.
+//
+// A file that is consumed again (re-analyzed) replaces its earlier lines, but the
+// earlier FileAnalysis can still read them, so that an analysis being replaced keeps
+// answering consistently. Forget releases replaced lines once nothing reads them.
+// Released records stay in the spool file until they make up most of it, at which
+// point the file is rewritten with only the current ones.
+type LineSpool struct {
+	mu        sync.Mutex
+	budget    int64
+	inMemory  int64
+	compactAt int64 // Bytes of released records that justify rewriting the spool file
+	file      *os.File
+	offset    int64
+	garbage   int64 // Bytes of the spool file taken by released records
+	current   map[string]spoolEntry
+	replaced  []spoolEntry // Superseded by re-analysis but not yet forgotten
+}
+
+// spoolEntry records one file's lines: their estimated size while held in memory,
+// or their location inside the spool file once spilled.
+type spoolEntry struct {
+	owner    *models.FileAnalysis
+	resident int64
+	spilled  bool
+	offset   int64
+	length   int64
+}
+
+// minSpoolCompaction is how large released records must grow before the spool file
+// is rewritten, so small spools aren't rewritten over and over.
+const minSpoolCompaction = 16 * 1024 * 1024
+
+// NewLineSpool creates a spool that keeps at most budget bytes of line detail in memory.
+// A budget of zero or less keeps everything in memory.
+func NewLineSpool(budget int64) *LineSpool {
+	return &LineSpool{
+		budget:    budget,
+		compactAt: minSpoolCompaction,
+		current:   make(map[string]spoolEntry),
+	}
+}
+
+// Consume keeps the file's lines in memory while within budget, and spills them otherwise.
+func (s *LineSpool) Consume(file *models.FileAnalysis) error {
+	if s.budget <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A re-analyzed file's earlier lines are superseded, but stay readable until forgotten
+	if entry, ok := s.current[file.Path]; ok {
+		s.replaced = append(s.replaced, entry)
+		delete(s.current, file.Path)
+	}
+	if len(file.LineHistories) == 0 {
+		return nil
+	}
+
+	size := estimateLineHistoriesSize(file.LineHistories)
+	if s.inMemory+size <= s.budget {
+		s.inMemory += size
+		s.current[file.Path] = spoolEntry{owner: file, resident: size}
+		return nil
+	}
+
+	if s.file == nil {
+		f, err := os.CreateTemp("", "ship-of-theseus-lines-*.ndjson")
+		if err != nil {
+			return fmt.Errorf("failed to create spool file: %w", err)
+		}
+		s.file = f
+	}
+
+	data, err := json.Marshal(file.LineHistories)
+	if err != nil {
+		return fmt.Errorf("failed to encode lines for %s: %w", file.Path, err)
+	}
+	data = append(data, '\n')
+
+	if _, err := s.file.WriteAt(data, s.offset); err != nil {
+		return fmt.Errorf("failed to spool lines for %s: %w", file.Path, err)
+	}
+
+	s.current[file.Path] = spoolEntry{owner: file, spilled: true, offset: s.offset, length: int64(len(data))}
+	s.offset += int64(len(data))
+
+	// Line detail now lives on disk
+	file.LineHistories = nil
+	return nil
+}
+
+// Forget releases the lines of files that no longer exist, such as files deleted
+// by new commits, along with every file's lines replaced by re-analysis. Call it
+// once nothing reads the previous analysis any more.
+func (s *LineSpool) Forget(paths ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, path := range paths {
+		if entry, ok := s.current[path]; ok {
+			s.release(entry)
+			delete(s.current, path)
+		}
+	}
+	for _, entry := range s.replaced {
+		s.release(entry)
+	}
+	s.replaced = nil
+
+	if s.garbage > 0 && s.garbage >= s.compactAt && s.garbage > s.offset/2 {
+		return s.compact()
+	}
+	return nil
+}
+
+// release returns an entry's lines to the budget, or marks its spilled record as
+// garbage. The caller must hold s.mu.
+func (s *LineSpool) release(entry spoolEntry) {
+	if entry.spilled {
+		s.garbage += entry.length
+	} else {
+		s.inMemory -= entry.resident
+	}
+}
+
+// compact rewrites the spool file with only the current records, dropping released
+// ones. The caller must hold s.mu, and no replaced records may be pending.
+func (s *LineSpool) compact() error {
+	f, err := os.CreateTemp("", "ship-of-theseus-lines-*.ndjson")
+	if err != nil {
+		return fmt.Errorf("failed to create spool file: %w", err)
+	}
+
+	current := make(map[string]spoolEntry, len(s.current))
+	var offset int64
+	for path, entry := range s.current {
+		if entry.spilled {
+			data := make([]byte, entry.length)
+			if _, err = s.file.ReadAt(data, entry.offset); err == nil || err == io.EOF {
+				_, err = f.WriteAt(data, offset)
+			}
+			if err != nil {
+				f.Close()
+				os.Remove(f.Name())
+				return fmt.Errorf("failed to compact spool file: %w", err)
+			}
+			entry.offset = offset
+			offset += entry.length
+		}
+		current[path] = entry
+	}
+
+	old := s.file.Name()
+	s.file.Close()
+	os.Remove(old)
+
+	s.file, s.current, s.offset, s.garbage = f, current, offset, 0
+	return nil
+}
+
+// Close does nothing; spilled lines remain readable until Remove is called.
+func (s *LineSpool) Close() error {
+	return nil
+}
+
+// Spilled reports whether any file's lines were written to disk.
+func (s *LineSpool) Spilled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset > 0
+}
+
+// Lines returns the line histories of a consumed file, reading them back from disk if
+// they were spilled. The second return value is false if the file was never spilled,
+// in which case its lines (if any) are still on the FileAnalysis itself.
+func (s *LineSpool) Lines(file *models.FileAnalysis) ([]*models.LineHistory, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.current[file.Path]
+	if !ok || entry.owner != file {
+		// An earlier analysis of the file, replaced but not yet forgotten
+		ok = false
+		for _, old := range s.replaced {
+			if old.owner == file {
+				entry, ok = old, true
+			}
+		}
+	}
+	if !ok || !entry.spilled {
+		return nil, false, nil
+	}
+
+	data := make([]byte, entry.length)
+	if _, err := s.file.ReadAt(data, entry.offset); err != nil && err != io.EOF {
+		return nil, true, fmt.Errorf("failed to read spooled lines for %s: %w", file.Path, err)
+	}
+
+	var lines []*models.LineHistory
+	if err := json.Unmarshal(data, &lines); err != nil {
+		return nil, true, fmt.Errorf("failed to decode spooled lines for %s: %w", file.Path, err)
+	}
+	return lines, true, nil
+}
+
+// Remove deletes the spool file. The spool must not be used afterwards.
+func (s *LineSpool) Remove() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	name := s.file.Name()
+	s.file.Close()
+	s.file = nil
+	s.current = make(map[string]spoolEntry)
+	s.replaced = nil
+	s.inMemory, s.offset, s.garbage = 0, 0, 0
+	return os.Remove(name)
+}
+
+// estimateLineHistoriesSize approximates the heap footprint of a file's line histories.
+// The fixed overhead covers the struct, its two time.Time values and two commit hashes.
+func estimateLineHistoriesSize(lines []*models.LineHistory) int64 {
+	const perLineOverhead = 200
+
+	var size int64
+	for _, line := range lines {
+		size += perLineOverhead + int64(len(line.CurrentLine)+len(line.OriginalLine))
+	}
+	return size
+}
@@ -0,0 +1,272 @@
+package analyzer
+
+import (
+	"errors"
+	"math"
+	"os"
+	"ship-of-theseus/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestAggregator(t *testing.T) {
+	aggregator := NewAggregator()
+	files := []*models.FileAnalysis{
+		{Path: "a.go", TotalLines: 30, OriginalLines: 15, AvgSimilarity: 0.5},
+		{Path: "b.go", TotalLines: 10, OriginalLines: 9, AvgSimilarity: 0.9},
+		{Path: "empty.go"},
+	}
+	for _, file := range files {
+		if err := aggregator.Consume(file); err != nil {
+			t.Fatalf("Consume failed: %v", err)
+		}
+	}
+
+	analysis := aggregator.Analysis()
+	if analysis.TotalLines != 40 || analysis.OriginalLines != 24 {
+		t.Errorf("totals = %d lines, %d original, want 40 and 24", analysis.TotalLines, analysis.OriginalLines)
+	}
+	// Weighted by lines: (30×0.5 + 10×0.9) / 40
+	if math.Abs(analysis.AverageSimilarity-0.6) > 1e-9 {
+		t.Errorf("AverageSimilarity = %v, want 0.6", analysis.AverageSimilarity)
+	}
+	if len(analysis.FileAnalyses) != 3 || analysis.FileAnalyses[0] != files[0] {
+		t.Errorf("FileAnalyses = %v, want the consumed files in order", analysis.FileAnalyses)
+	}
+
+	if empty := NewAggregator().Analysis(); empty.TotalLines != 0 || empty.AverageSimilarity != 0 {
+		t.Errorf("empty analysis = %+v, want zero totals", empty)
+	}
+}
+
+// recordingSink records the files it consumes and whether their lines were present.
+type recordingSink struct {
+	paths     []string
+	withLines int
+	closed    int
+	err       error
+}
+
+func (r *recordingSink) Consume(file *models.FileAnalysis) error {
+	r.paths = append(r.paths, file.Path)
+	if len(file.LineHistories) > 0 {
+		r.withLines++
+	}
+	return r.err
+}
+
+func (r *recordingSink) Close() error {
+	r.closed++
+	return nil
+}
+
+func TestAnalyzeSinks(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.write("a.go", "package a\n\nfunc A() {}\n")
+	repo.write("b.go", "package b\n")
+	repo.write("logo.png", "not analyzed\n")
+	repo.commit("Add files")
+
+	sink := &recordingSink{}
+	analysis, err := AnalyzeRepositoryWithOptions(repo.dir, Options{
+		NumWorkers:        2,
+		Sinks:             []ResultSink{sink},
+		DropLineHistories: true,
+	})
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions failed: %v", err)
+	}
+
+	if len(sink.paths) != 2 || sink.withLines != 2 || sink.closed != 1 {
+		t.Errorf("sink saw %v (%d with lines), closed %d times; want both source files with lines, closed once",
+			sink.paths, sink.withLines, sink.closed)
+	}
+	// Line detail was dropped after the sinks saw it; totals are unaffected
+	for _, file := range analysis.FileAnalyses {
+		if file.LineHistories != nil {
+			t.Errorf("%s kept its line histories", file.Path)
+		}
+	}
+	if len(analysis.FileAnalyses) != 2 || analysis.TotalLines != 3 {
+		t.Errorf("analysis = %d files, %d lines, want 2 files and 3 lines", len(analysis.FileAnalyses), analysis.TotalLines)
+	}
+
+	failing := &recordingSink{err: errors.New("disk full")}
+	if _, err := AnalyzeRepositoryWithOptions(repo.dir, Options{NumWorkers: 2, Sinks: []ResultSink{failing}}); err == nil {
+		t.Error("analysis with a failing sink succeeded, want an error")
+	}
+	if len(failing.paths) != 1 || failing.closed != 1 {
+		t.Errorf("failing sink saw %v and was closed %d times, want one file and closed once", failing.paths, failing.closed)
+	}
+}
+
+// spoolFile returns a file analysis with n lines, each reading text.
+func spoolFile(path, text string, n int) *models.FileAnalysis {
+	file := &models.FileAnalysis{Path: path, TotalLines: n}
+	for i := 0; i < n; i++ {
+		file.LineHistories = append(file.LineHistories, &models.LineHistory{CurrentLine: text, CurrentLineNum: i + 1})
+	}
+	return file
+}
+
+// spooledText reads a file's lines back from the spool and returns the text of its
+// first line, or "" when they were never spilled.
+func spooledText(t *testing.T, s *LineSpool, file *models.FileAnalysis) string {
+	t.Helper()
+	lines, spilled, err := s.Lines(file)
+	if err != nil {
+		t.Fatalf("Lines(%s) failed: %v", file.Path, err)
+	}
+	if !spilled {
+		return ""
+	}
+	if len(lines) != file.TotalLines {
+		t.Fatalf("Lines(%s) returned %d lines, want %d", file.Path, len(lines), file.TotalLines)
+	}
+	return lines[0].CurrentLine
+}
+
+// newTestSpool returns a spool whose budget holds one 2-line file and no more.
+func newTestSpool(t *testing.T) *LineSpool {
+	s := NewLineSpool(estimateLineHistoriesSize(spoolFile("", "x", 2).LineHistories))
+	t.Cleanup(func() { s.Remove() })
+	return s
+}
+
+func consume(t *testing.T, s *LineSpool, files ...*models.FileAnalysis) {
+	t.Helper()
+	for _, file := range files {
+		if err := s.Consume(file); err != nil {
+			t.Fatalf("Consume(%s) failed: %v", file.Path, err)
+		}
+	}
+}
+
+func TestLineSpoolSpill(t *testing.T) {
+	s := newTestSpool(t)
+	a, b := spoolFile("a.go", "x", 2), spoolFile("b.go", "y", 3)
+	consume(t, s, a, b)
+
+	if a.LineHistories == nil || spooledText(t, s, a) != "" {
+		t.Error("a.go fits the budget but was spilled")
+	}
+	if b.LineHistories != nil {
+		t.Error("b.go exceeds the budget but kept its lines in memory")
+	}
+	if got := spooledText(t, s, b); got != "y" {
+		t.Errorf("spilled b.go reads back %q, want y", got)
+	}
+	if !s.Spilled() {
+		t.Error("Spilled() = false after spilling b.go")
+	}
+
+	// Without a budget everything stays in memory
+	unbounded := NewLineSpool(0)
+	c := spoolFile("c.go", "z", 100)
+	consume(t, unbounded, c)
+	if c.LineHistories == nil || unbounded.Spilled() {
+		t.Error("a spool without a budget spilled lines")
+	}
+}
+
+func TestLineSpoolReplace(t *testing.T) {
+	s := newTestSpool(t)
+	a, b := spoolFile("a.go", "x", 2), spoolFile("b.go", "old", 3)
+	consume(t, s, a, b)
+
+	// Re-analysis replaces b.go; the earlier analysis still reads its own lines
+	b2 := spoolFile("b.go", "new", 4)
+	consume(t, s, b2)
+	if got := spooledText(t, s, b2); got != "new" {
+		t.Errorf("re-analyzed b.go reads back %q, want new", got)
+	}
+	if got := spooledText(t, s, b); got != "old" {
+		t.Errorf("replaced b.go reads back %q before Forget, want old", got)
+	}
+
+	if err := s.Forget(); err != nil {
+		t.Fatalf("Forget failed: %v", err)
+	}
+	if got := spooledText(t, s, b); got != "" {
+		t.Errorf("replaced b.go reads back %q after Forget, want nothing", got)
+	}
+	if got := spooledText(t, s, b2); got != "new" {
+		t.Errorf("re-analyzed b.go reads back %q after Forget, want new", got)
+	}
+
+	// A replaced in-memory file keeps its share of the budget until forgotten
+	a2 := spoolFile("a.go", "x2", 2)
+	consume(t, s, a2)
+	if a2.LineHistories != nil {
+		t.Error("re-analyzed a.go stayed in memory while the replaced lines still count")
+	}
+	consume(t, s, spoolFile("c.go", "z", 1))
+	if err := s.Forget(); err != nil {
+		t.Fatalf("Forget failed: %v", err)
+	}
+	d := spoolFile("d.go", "w", 2)
+	consume(t, s, d)
+	if d.LineHistories == nil {
+		t.Error("d.go was spilled although forgetting a.go freed the budget")
+	}
+}
+
+func TestLineSpoolForget(t *testing.T) {
+	s := newTestSpool(t)
+	a, b := spoolFile("a.go", "x", 2), spoolFile("b.go", "y", 3)
+	consume(t, s, a, b)
+
+	if err := s.Forget("a.go", "b.go", "never-consumed.go"); err != nil {
+		t.Fatalf("Forget failed: %v", err)
+	}
+	if got := spooledText(t, s, b); got != "" {
+		t.Errorf("deleted b.go reads back %q, want nothing", got)
+	}
+
+	// Forgetting a.go returned its lines to the budget
+	c := spoolFile("c.go", "z", 2)
+	consume(t, s, c)
+	if c.LineHistories == nil {
+		t.Error("c.go was spilled although forgetting a.go freed the budget")
+	}
+}
+
+func TestLineSpoolCompact(t *testing.T) {
+	s := newTestSpool(t)
+	s.compactAt = 1
+	consume(t, s, spoolFile("a.go", "x", 2))
+
+	// Spill three files, then replace two of them, so most of the spool file is stale
+	for _, path := range []string{"b.go", "c.go"} {
+		consume(t, s, spoolFile(path, strings.Repeat("old ", 50), 10))
+	}
+	d := spoolFile("d.go", "new d.go", 10)
+	consume(t, s, d)
+	current := []*models.FileAnalysis{d}
+	for _, path := range []string{"b.go", "c.go"} {
+		file := spoolFile(path, "new "+path, 3)
+		consume(t, s, file)
+		current = append(current, file)
+	}
+
+	before := s.offset
+	if err := s.Forget(); err != nil {
+		t.Fatalf("Forget failed: %v", err)
+	}
+	if s.garbage != 0 || s.offset >= before {
+		t.Errorf("spool file holds %d bytes (%d garbage) after Forget, want fewer than %d and no garbage", s.offset, s.garbage, before)
+	}
+	info, err := os.Stat(s.file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != s.offset {
+		t.Errorf("compacted spool file is %d bytes, want %d", info.Size(), s.offset)
+	}
+
+	for _, file := range current {
+		if got := spooledText(t, s, file); got != "new "+file.Path {
+			t.Errorf("%s reads back %q after compaction, want %q", file.Path, got, "new "+file.Path)
+		}
+	}
+}
@@ -4,24 +4,46 @@ import (
 	"fmt"
 	"math"
 	"ship-of-theseus/internal/models"
+	"sort"
+	"time"
 )
 
+// Sampling chooses the commits a timeline is plotted at.
+type Sampling struct {
+	Every    int    // Every Nth commit, always including the most recent
+	Interval string // Instead, the last commit of each calendar "week", "month" or "quarter"
+	Tags     bool   // Instead, release tags, ending with the most recent commit
+	TagGlob  string // With Tags, only tags matching this glob (e.g. "v*"); every tag when empty
+}
+
+// Intervals are the calendar buckets Sampling.Interval accepts.
+var Intervals = []string{"week", "month", "quarter"}
+
+// snapshotPoint is a commit the timeline is plotted at.
+type snapshotPoint struct {
+	CommitInfo
+	Label string // Axis label, such as a tag name; the date when empty
+}
+
 // GenerateHistoricalSnapshots creates a timeline showing how code originality changed over time.
 // Uses heuristic estimation rather than full re-analysis of each commit for performance.
 //
 // Parameters:
 //   - repoPath: Path to git repository
-//   - sampleRate: Analyze every Nth commit (e.g., 50 = every 50th commit)
-//   - currentOriginalPct: The current percentage of original code (from main analysis)
+//   - sampling: Which commits to plot (e.g., every 50th commit, or each release tag)
+//   - totalLines, originalLines: The current lines of code and original lines (from main analysis)
+//   - filter: Which commits make up the history (e.g. a time window)
 //
 // Algorithm:
-//  1. Get all commits in repository
-//  2. Sample every Nth commit
+//  1. Get all commits in repository that pass the filter
+//  2. Sample every Nth commit (or the last of each calendar interval, or the release tags)
 //  3. For each sample, estimate originality using:
 //     - Time decay: older commits had more "original" code
 //     - Churn factor: commits with high churn reduce originality more
 //  4. Apply formula: originalPct = 100 * (1 - ageRatio * decayRate) * churnFactor
 //  5. Minimum baseline: 10% (code rarely drops below this)
+//  6. Count lines, churn and contributors from the history between samples
+//  7. The most recent sample is measured: it carries the current lines of code
 //
 // PHILOSOPHICAL NOTE: Originality Can Increase Over Time
 // ======================================================
@@ -31,31 +53,36 @@ import (
 // Theseus getting its old planks back. This measures "snapshot similarity to origin", not
 // "accumulated irreversible change". A codebase that simplifies after experimentation is
 // becoming MORE original, and that's worth celebrating.
-func GenerateHistoricalSnapshots(repoPath string, sampleRate int, currentOriginalPct float64) ([]models.Snapshot, error) {
-	// Get all commits
-	allCommits, err := GetAllCommits(repoPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get commits: %w", err)
+func GenerateHistoricalSnapshots(repoPath string, sampling Sampling, totalLines, originalLines int, filter CommitFilter) ([]models.Snapshot, error) {
+	currentOriginalPct := 0.0
+	if totalLines > 0 {
+		currentOriginalPct = float64(originalLines) / float64(totalLines) * 100.0
 	}
 
-	if len(allCommits) == 0 {
-		return nil, fmt.Errorf("no commits found in repository")
-	}
-
-	// Sample commits
-	var sampledCommits []CommitInfo
-	for i := len(allCommits) - 1; i >= 0; i -= sampleRate {
-		sampledCommits = append(sampledCommits, allCommits[i])
+	var sampledCommits []snapshotPoint
+	var err error
+	switch {
+	case sampling.Tags:
+		sampledCommits, err = sampleTags(repoPath, sampling.TagGlob, filter)
+	case sampling.Interval != "":
+		sampledCommits, err = sampleInterval(repoPath, sampling.Interval, filter)
+	default:
+		sampledCommits, err = sampleEvery(repoPath, sampling.Every, filter)
 	}
-
-	// Always include the most recent commit
-	if len(sampledCommits) == 0 || sampledCommits[len(sampledCommits)-1].Hash != allCommits[0].Hash {
-		sampledCommits = append(sampledCommits, allCommits[0])
+	if err != nil {
+		return nil, err
 	}
 
 	// Generate snapshots using heuristic estimation
 	snapshots := make([]models.Snapshot, 0, len(sampledCommits))
 
+	// Lines are counted from the diffs between consecutive samples, starting from the
+	// empty tree, and churn and contributors from the commits between them; all are
+	// left out if any of the git commands fail
+	emptyTree, statsErr := GetEmptyTree(repoPath)
+	previous, lines := emptyTree, 0
+	authors := make(map[string]bool)
+
 	for i, commit := range sampledCommits {
 		// Calculate age ratio (0.0 = oldest, 1.0 = newest)
 		ageRatio := float64(i) / float64(len(sampledCommits)-1)
@@ -105,16 +132,257 @@ func GenerateHistoricalSnapshots(repoPath string, sampleRate int, currentOrigina
 			}
 		}
 
-		snapshots = append(snapshots, models.Snapshot{
+		snapshot := models.Snapshot{
 			CommitHash:  commit.Hash,
 			Date:        commit.Date,
 			OriginalPct: originalPct,
-		})
+			Label:       commit.Label,
+		}
+
+		if statsErr == nil {
+			statsErr = addHistoryStats(repoPath, &snapshot, previous, emptyTree, filter.FirstParent, &lines, authors)
+			previous = commit.Hash
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if statsErr != nil {
+		for i := range snapshots {
+			s := &snapshots[i]
+			s.Lines, s.LinesAdded, s.LinesRemoved, s.Contributors = 0, 0, 0, 0
+		}
+	}
+
+	if len(snapshots) > 0 {
+		last := &snapshots[len(snapshots)-1]
+		last.TotalLines, last.OriginalLines = totalLines, originalLines
 	}
 
 	return snapshots, nil
 }
 
+// addHistoryStats fills in the line count, churn and contributor count of a snapshot
+// from the history since the previous sample (the empty tree before the first). The
+// line count follows the net diff between the samples; churn sums every commit in
+// between. lines and authors carry the running line count and the authors seen so far.
+func addHistoryStats(repoPath string, snapshot *models.Snapshot, previous, emptyTree string, firstParent bool, lines *int, authors map[string]bool) error {
+	added, removed, err := GetDiffStats(repoPath, previous, snapshot.CommitHash)
+	if err != nil {
+		return err
+	}
+	*lines += added - removed
+	snapshot.Lines = *lines
+
+	if previous != emptyTree {
+		snapshot.LinesAdded, snapshot.LinesRemoved, err = GetChurn(repoPath, previous, snapshot.CommitHash, firstParent)
+		if err != nil {
+			return err
+		}
+	}
+
+	since := previous
+	if previous == emptyTree {
+		since = ""
+	}
+	newAuthors, err := GetAuthors(repoPath, since, snapshot.CommitHash)
+	if err != nil {
+		return err
+	}
+	for _, author := range newAuthors {
+		authors[author] = true
+	}
+
+	snapshot.Contributors = len(authors)
+	return nil
+}
+
+// sampleEvery samples every Nth commit of the history, oldest first, always including
+// the most recent commit.
+func sampleEvery(repoPath string, sampleRate int, filter CommitFilter) ([]snapshotPoint, error) {
+	// Get all commits
+	allCommits, err := GetAllCommits(repoPath, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	if len(allCommits) == 0 {
+		return nil, fmt.Errorf("no commits found in repository")
+	}
+
+	// Sample commits
+	var sampledCommits []snapshotPoint
+	for i := len(allCommits) - 1; i >= 0; i -= sampleRate {
+		sampledCommits = append(sampledCommits, snapshotPoint{CommitInfo: allCommits[i]})
+	}
+
+	// Always include the most recent commit
+	if len(sampledCommits) == 0 || sampledCommits[len(sampledCommits)-1].Hash != allCommits[0].Hash {
+		sampledCommits = append(sampledCommits, snapshotPoint{CommitInfo: allCommits[0]})
+	}
+
+	return sampledCommits, nil
+}
+
+// sampleInterval samples the last commit of each calendar interval, oldest first, and
+// labels it with the interval. Every interval between the first and last commit gets
+// a point, so the timeline is evenly spaced in time; an interval without commits
+// carries the previous interval's commit forward.
+func sampleInterval(repoPath, interval string, filter CommitFilter) ([]snapshotPoint, error) {
+	allCommits, err := GetAllCommits(repoPath, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	if len(allCommits) == 0 {
+		return nil, fmt.Errorf("no commits found in repository")
+	}
+
+	return bucketCommits(allCommits, interval)
+}
+
+// bucketCommits picks the last of commits in each calendar interval from the first
+// commit's to the last commit's, carrying the previous pick forward through
+// intervals without commits.
+func bucketCommits(commits []CommitInfo, interval string) ([]snapshotPoint, error) {
+	// Commits from several refs aren't strictly ordered by date
+	commits = append([]CommitInfo(nil), commits...)
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Date.Before(commits[j].Date)
+	})
+
+	start, err := intervalStart(commits[0].Date, interval)
+	if err != nil {
+		return nil, err
+	}
+	last := commits[len(commits)-1].Date
+
+	var sampledCommits []snapshotPoint
+	var current CommitInfo
+	for i := 0; !start.After(last); {
+		next := nextInterval(start, interval)
+		for ; i < len(commits) && commits[i].Date.Before(next); i++ {
+			current = commits[i]
+		}
+
+		label, err := intervalLabel(start, interval)
+		if err != nil {
+			return nil, err
+		}
+		sampledCommits = append(sampledCommits, snapshotPoint{CommitInfo: current, Label: label})
+		start = next
+	}
+
+	return sampledCommits, nil
+}
+
+// intervalStart returns the start of the calendar interval containing t, in local
+// time: midnight on the Monday of its week, or the first day of its month or quarter.
+func intervalStart(t time.Time, interval string) (time.Time, error) {
+	t = t.Local()
+	switch interval {
+	case "week":
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7), nil
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local), nil
+	case "quarter":
+		return time.Date(t.Year(), (t.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.Local), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown interval %q", interval)
+	}
+}
+
+// nextInterval returns the start of the interval following the one starting at start.
+func nextInterval(start time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 3, 0)
+	}
+}
+
+// intervalLabel names the calendar interval containing t, in local time: the date
+// its week starts (on Monday), its month, or its quarter.
+func intervalLabel(t time.Time, interval string) (string, error) {
+	start, err := intervalStart(t, interval)
+	if err != nil {
+		return "", err
+	}
+	switch interval {
+	case "week":
+		return start.Format("2006-01-02"), nil
+	case "month":
+		return start.Format("2006-01"), nil
+	default:
+		return fmt.Sprintf("%d Q%d", start.Year(), (int(start.Month())-1)/3+1), nil
+	}
+}
+
+// sampleTags samples the commit of each release tag matching glob, oldest first. The
+// timeline ends at the most recent commit, which the current originality measures,
+// so that commit follows the tags unless the last tag already points at it.
+func sampleTags(repoPath, glob string, filter CommitFilter) ([]snapshotPoint, error) {
+	tags, err := GetTags(repoPath, glob, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	end, endLabel, err := lastCommit(repoPath, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var sampledCommits []snapshotPoint
+	for _, tag := range tags {
+		// Tags on other branches may be newer than the code being measured
+		if !tag.Date.After(end.Date) {
+			sampledCommits = append(sampledCommits, snapshotPoint{CommitInfo: tag.CommitInfo, Label: tag.Name})
+		}
+	}
+
+	if len(sampledCommits) == 0 {
+		if glob != "" {
+			return nil, fmt.Errorf("no tags matching %q found", glob)
+		}
+		return nil, fmt.Errorf("no tags found in repository")
+	}
+
+	if sampledCommits[len(sampledCommits)-1].Hash != end.Hash {
+		sampledCommits = append(sampledCommits, snapshotPoint{CommitInfo: end, Label: endLabel})
+	}
+
+	return sampledCommits, nil
+}
+
+// lastCommit returns the most recent commit of the filter's history, the tip of its
+// branch (or HEAD) or the last commit made by Until, with a label for it.
+func lastCommit(repoPath string, filter CommitFilter) (CommitInfo, string, error) {
+	ref := "HEAD"
+	if filter.Branch != "" {
+		ref = filter.Branch
+	}
+
+	if filter.Until.IsZero() {
+		commit, err := GetCommit(repoPath, ref)
+		return commit, ref, err
+	}
+
+	hash, err := CommitBefore(repoPath, ref, filter.Until, filter.FirstParent)
+	if err == nil && hash == "" {
+		err = fmt.Errorf("no commits on %s at or before %s", ref, filter.Until.Format("2006-01-02"))
+	}
+	if err != nil {
+		return CommitInfo{}, "", err
+	}
+
+	commit, err := GetCommit(repoPath, hash)
+	return commit, hash[:7], err
+}
+
 // calculateChurnFactor converts commit churn (lines changed) into a factor between 0.0 and 1.0.
 // Higher churn = lower factor = more impact on originality.
 //
@@ -143,13 +411,8 @@ func calculateChurnFactor(totalChurn int) float64 {
 }
 
 // AddSnapshotsToAnalysis updates an analysis with historical snapshots.
-func AddSnapshotsToAnalysis(analysis *models.CodebaseAnalysis, repoPath string, sampleRate int) error {
-	currentPct := 0.0
-	if analysis.TotalLines > 0 {
-		currentPct = float64(analysis.OriginalLines) / float64(analysis.TotalLines) * 100.0
-	}
-
-	snapshots, err := GenerateHistoricalSnapshots(repoPath, sampleRate, currentPct)
+func AddSnapshotsToAnalysis(analysis *models.CodebaseAnalysis, repoPath string, sampling Sampling, filter CommitFilter) error {
+	snapshots, err := GenerateHistoricalSnapshots(repoPath, sampling, analysis.TotalLines, analysis.OriginalLines, filter)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,187 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIntervalLabel(t *testing.T) {
+	utc := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			panic(err)
+		}
+		return parsed
+	}
+	plus2 := time.FixedZone("+02:00", 2*60*60)
+
+	tests := []struct {
+		name     string
+		local    *time.Location
+		t        time.Time
+		interval string
+		want     string
+	}{
+		// 2024-01-01 is a Monday
+		{"week starts on Monday at midnight", time.UTC, utc("2024-01-01T00:00:00Z"), "week", "2024-01-01"},
+		{"week ends on Sunday", time.UTC, utc("2024-01-07T23:59:59Z"), "week", "2024-01-01"},
+		{"next week starts on Monday", time.UTC, utc("2024-01-08T00:00:00Z"), "week", "2024-01-08"},
+		{"week across a year boundary", time.UTC, utc("2023-12-31T12:00:00Z"), "week", "2023-12-25"},
+		{"week across a leap day", time.UTC, utc("2024-03-03T12:00:00Z"), "week", "2024-02-26"},
+		{"week in local time", plus2, utc("2024-01-07T23:30:00Z"), "week", "2024-01-08"},
+		{"week before local midnight", plus2, utc("2024-01-07T21:59:59Z"), "week", "2024-01-01"},
+
+		{"month", time.UTC, utc("2024-02-29T23:59:59Z"), "month", "2024-02"},
+		{"month in local time", plus2, utc("2024-02-29T23:00:00Z"), "month", "2024-03"},
+
+		{"first quarter", time.UTC, utc("2024-03-31T23:59:59Z"), "quarter", "2024 Q1"},
+		{"second quarter", time.UTC, utc("2024-04-01T00:00:00Z"), "quarter", "2024 Q2"},
+		{"fourth quarter", time.UTC, utc("2024-12-31T12:00:00Z"), "quarter", "2024 Q4"},
+		{"quarter in local time", plus2, utc("2024-12-31T23:00:00Z"), "quarter", "2025 Q1"},
+	}
+
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			time.Local = tt.local
+			got, err := intervalLabel(tt.t, tt.interval)
+			if err != nil {
+				t.Fatalf("intervalLabel(%v, %q) failed: %v", tt.t, tt.interval, err)
+			}
+			if got != tt.want {
+				t.Errorf("intervalLabel(%v, %q) = %q, want %q", tt.t, tt.interval, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := intervalLabel(utc("2024-01-01T00:00:00Z"), "year"); err == nil {
+		t.Error("intervalLabel with an unknown interval succeeded, want an error")
+	}
+}
+
+func TestBucketCommits(t *testing.T) {
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.UTC
+
+	commit := func(hash, date string) CommitInfo {
+		d, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			panic(err)
+		}
+		return CommitInfo{Hash: hash, Date: d}
+	}
+
+	type point struct{ hash, label string }
+	tests := []struct {
+		name     string
+		commits  []CommitInfo // Newest first, as git log lists them
+		interval string
+		want     []point
+	}{
+		{
+			name: "last commit of each month",
+			commits: []CommitInfo{
+				commit("c", "2024-02-03T10:00:00Z"),
+				commit("b", "2024-01-31T23:59:59Z"),
+				commit("a", "2024-01-10T10:00:00Z"),
+			},
+			interval: "month",
+			want:     []point{{"b", "2024-01"}, {"c", "2024-02"}},
+		},
+		{
+			name: "quiet months carry the previous commit forward",
+			commits: []CommitInfo{
+				commit("c", "2024-05-02T10:00:00Z"),
+				commit("b", "2024-01-20T10:00:00Z"),
+				commit("a", "2024-01-10T10:00:00Z"),
+			},
+			interval: "month",
+			want: []point{
+				{"b", "2024-01"}, {"b", "2024-02"}, {"b", "2024-03"}, {"b", "2024-04"}, {"c", "2024-05"},
+			},
+		},
+		{
+			name: "a quiet year of quarters",
+			commits: []CommitInfo{
+				commit("b", "2024-02-01T10:00:00Z"),
+				commit("a", "2022-11-15T10:00:00Z"),
+			},
+			interval: "quarter",
+			want: []point{
+				{"a", "2022 Q4"}, {"a", "2023 Q1"}, {"a", "2023 Q2"}, {"a", "2023 Q3"}, {"a", "2023 Q4"}, {"b", "2024 Q1"},
+			},
+		},
+		{
+			name: "weeks start on Monday",
+			commits: []CommitInfo{
+				commit("c", "2024-01-22T00:00:00Z"),
+				commit("b", "2024-01-07T23:59:59Z"),
+				commit("a", "2024-01-01T00:00:00Z"),
+			},
+			interval: "week",
+			want:     []point{{"b", "2024-01-01"}, {"b", "2024-01-08"}, {"b", "2024-01-15"}, {"c", "2024-01-22"}},
+		},
+		{
+			name: "commits out of date order",
+			commits: []CommitInfo{
+				commit("a", "2024-01-05T10:00:00Z"),
+				commit("b", "2024-03-05T10:00:00Z"),
+			},
+			interval: "month",
+			want:     []point{{"a", "2024-01"}, {"a", "2024-02"}, {"b", "2024-03"}},
+		},
+		{
+			name:     "a single commit",
+			commits:  []CommitInfo{commit("a", "2024-01-05T10:00:00Z")},
+			interval: "week",
+			want:     []point{{"a", "2024-01-01"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := bucketCommits(tt.commits, tt.interval)
+			if err != nil {
+				t.Fatalf("bucketCommits failed: %v", err)
+			}
+			var got []point
+			for _, p := range points {
+				got = append(got, point{p.Hash, p.Label})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bucketCommits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateHistoricalSnapshotsStats(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.write("a.go", "one\ntwo\nthree\n")
+	repo.commit("Add a.go")
+	repo.write("a.go", "one\nTWO\nthree\n")
+	repo.commit("Edit a.go")
+	repo.write("a.go", "one\ntwo\nthree\nfour\n")
+	repo.commit("Revert the edit and extend a.go")
+
+	snapshots, err := GenerateHistoricalSnapshots(repo.dir, Sampling{Every: 2}, 3, 2, CommitFilter{})
+	if err != nil {
+		t.Fatalf("GenerateHistoricalSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("got %d snapshots, want the first and the last commit", len(snapshots))
+	}
+
+	first, last := snapshots[0], snapshots[1]
+	if first.Lines != 3 || first.LinesAdded != 0 || first.TotalLines != 0 {
+		t.Errorf("first snapshot = %+v, want 3 lines, no churn and no measured size", first)
+	}
+	// Net, the last snapshot has one more line; the two commits changed more than that
+	if last.Lines != 4 || last.LinesAdded != 3 || last.LinesRemoved != 2 {
+		t.Errorf("last snapshot = %d lines +%d/-%d, want 4 lines +3/-2", last.Lines, last.LinesAdded, last.LinesRemoved)
+	}
+	if last.TotalLines != 3 || last.OriginalLines != 2 || last.Contributors != 1 {
+		t.Errorf("last snapshot = %+v, want the measured 3 lines (2 original) and 1 contributor", last)
+	}
+}
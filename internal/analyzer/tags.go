@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tag is a tag and the commit it points at.
+type Tag struct {
+	Name string
+	CommitInfo
+}
+
+// GetTags lists the tags matching glob (every tag when empty) whose commits are part of
+// the filter's history, oldest commit first. Annotated tags are peeled to their commit.
+// With filter.FirstParent, only tags on the mainline itself count: a tag on a merged
+// branch is reachable from the branch followed, but isn't one of its commits.
+func GetTags(repoPath, glob string, filter CommitFilter) ([]Tag, error) {
+	// Run: git -C <repo> for-each-ref [--merged=<branch>] --format=<name|object|date|peeled object|peeled date> refs/tags/<glob>
+	args := []string{"-C", repoPath, "for-each-ref",
+		"--format=%(refname:lstrip=2)|%(objectname)|%(committerdate:unix)|%(*objectname)|%(*committerdate:unix)"}
+	if filter.Branch != "" {
+		args = append(args, "--merged="+filter.Branch)
+	}
+	pattern := "refs/tags"
+	if glob != "" {
+		pattern += "/" + glob
+	}
+
+	var mainline map[string]bool
+	if filter.FirstParent {
+		ref := filter.Branch
+		if ref == "" {
+			ref = "HEAD"
+		}
+		var err error
+		if mainline, err = firstParentCommits(repoPath, ref); err != nil {
+			return nil, err
+		}
+	}
+	cmd := exec.Command("git", append(args, pattern)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+
+	var tags []Tag
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		parts := strings.Split(scanner.Text(), "|")
+		if len(parts) != 5 {
+			continue
+		}
+
+		// Annotated tags point at a tag object; the commit is its peeled object
+		hash, date := parts[1], parts[2]
+		if parts[3] != "" {
+			hash, date = parts[3], parts[4]
+		}
+
+		// Tags of trees or blobs have no commit date
+		timestamp, err := strconv.ParseInt(date, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if mainline != nil && !mainline[hash] {
+			continue
+		}
+
+		tag := Tag{Name: parts[0], CommitInfo: CommitInfo{Hash: hash, Date: time.Unix(timestamp, 0)}}
+		if (!filter.Since.IsZero() && tag.Date.Before(filter.Since)) || (!filter.Until.IsZero() && tag.Date.After(filter.Until)) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].Date.Before(tags[j].Date)
+	})
+	return tags, nil
+}
+
+// firstParentCommits returns the set of commits on ref's first-parent chain.
+func firstParentCommits(repoPath, ref string) (map[string]bool, error) {
+	// Run: git -C <repo> rev-list --first-parent <ref>
+	output, err := exec.Command("git", "-C", repoPath, "rev-list", "--first-parent", ref).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list failed for %s: %w", ref, err)
+	}
+
+	commits := make(map[string]bool)
+	for _, hash := range strings.Fields(string(output)) {
+		commits[hash] = true
+	}
+	return commits, nil
+}
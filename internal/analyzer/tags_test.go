@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetTags(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.write("main.go", "package main\n")
+	repo.commit("Initial commit")
+	repo.git("tag", "-a", "-m", "Release 1", "v1")
+
+	repo.git("checkout", "-q", "-b", "feature")
+	repo.write("feature.go", "package main\n")
+	repo.commit("Add a feature")
+	repo.git("tag", "feature-done")
+
+	repo.git("checkout", "-q", "main")
+	repo.write("main.go", "package main\n\nfunc main() {}\n")
+	repo.commit("Add main")
+	repo.days++
+	repo.git("merge", "-q", "--no-ff", "-m", "Merge feature", "feature")
+	repo.git("tag", "-a", "-m", "Release 2", "v2")
+
+	repo.git("checkout", "-q", "-b", "unmerged", "v1")
+	repo.write("other.go", "package main\n")
+	repo.commit("Experiment")
+	repo.git("tag", "experiment")
+	repo.git("checkout", "-q", "main")
+
+	tests := []struct {
+		name   string
+		glob   string
+		filter CommitFilter
+		want   []string
+	}{
+		{"every tag", "", CommitFilter{}, []string{"v1", "feature-done", "v2", "experiment"}},
+		{"glob", "v*", CommitFilter{}, []string{"v1", "v2"}},
+		{"branch", "", CommitFilter{Branch: "feature"}, []string{"v1", "feature-done"}},
+		{"merged into the branch", "", CommitFilter{Branch: "main"}, []string{"v1", "feature-done", "v2"}},
+		{"first parent leaves out merged branches", "", CommitFilter{FirstParent: true}, []string{"v1", "v2"}},
+		{"first parent of a branch", "", CommitFilter{Branch: "feature", FirstParent: true}, []string{"v1", "feature-done"}},
+		{"since", "", CommitFilter{Since: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)}, []string{"v2", "experiment"}},
+		{"until", "", CommitFilter{Until: time.Date(2024, 1, 3, 23, 0, 0, 0, time.UTC)}, []string{"v1", "feature-done"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := GetTags(repo.dir, tt.glob, tt.filter)
+			if err != nil {
+				t.Fatalf("GetTags failed: %v", err)
+			}
+			var got []string
+			for _, tag := range tags {
+				got = append(got, tag.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Annotated tags are peeled to their commit
+	tags, err := GetTags(repo.dir, "v2", CommitFilter{})
+	if err != nil {
+		t.Fatalf("GetTags failed: %v", err)
+	}
+	if merge := repo.git("rev-parse", "main"); len(tags) != 1 || tags[0].Hash != merge {
+		t.Errorf("v2 = %+v, want the merge commit %s", tags, merge)
+	}
+}
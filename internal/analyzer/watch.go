@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"ship-of-theseus/internal/filter"
+	"ship-of-theseus/internal/models"
+	"strings"
+	"time"
+)
+
+// RepoState identifies what an analysis was computed from: HEAD, which determines
+// the analyzed files, and the refs, which determine the commits on the timeline.
+type RepoState struct {
+	Head string
+	Refs string // Every ref except notes, one "<hash> <name>" per line
+}
+
+// Change describes the difference between two repository states.
+type Change struct {
+	From    RepoState
+	To      RepoState
+	Changed []string // Files added or modified between the two HEADs
+	Deleted []string // Files removed between the two HEADs
+}
+
+// GetRepoState returns the current HEAD and refs of a repository.
+func GetRepoState(repoPath string) (RepoState, error) {
+	// Run: git -C <repo> rev-parse HEAD
+	head, err := exec.Command("git", "-C", repoPath, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return RepoState{}, fmt.Errorf("git rev-parse failed: %w", err)
+	}
+
+	// Run: git -C <repo> for-each-ref --format=%(objectname) %(refname)
+	output, err := exec.Command("git", "-C", repoPath, "for-each-ref", "--format=%(objectname) %(refname)").Output()
+	if err != nil {
+		return RepoState{}, fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+
+	// Notes commits aren't history (see GetAllCommits), and writing one shouldn't
+	// look like a change
+	var refs []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if !strings.Contains(line, " refs/notes/") {
+			refs = append(refs, line)
+		}
+	}
+
+	return RepoState{Head: strings.TrimSpace(string(head)), Refs: strings.Join(refs, "\n")}, nil
+}
+
+// GetChangedFiles lists the files that differ between two commits, split into files
+// that were added or modified and files that were deleted. Renames count as a
+// deletion of the old path and an addition of the new one.
+func GetChangedFiles(repoPath, from, to string) ([]string, []string, error) {
+	// Run: git -C <repo> diff --name-status --no-renames <from> <to>
+	cmd := exec.Command("git", "-C", repoPath, "diff", "--name-status", "--no-renames", from, to)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("git diff failed for %s..%s: %w", from, to, err)
+	}
+
+	var changed, deleted []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		status, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if status == "D" {
+			deleted = append(deleted, path)
+		} else {
+			changed = append(changed, path)
+		}
+	}
+
+	return changed, deleted, nil
+}
+
+// Watch polls the repository every interval, starting from state, and calls onChange
+// whenever HEAD or any ref moves. It runs until onChange returns an error.
+func Watch(repoPath string, state RepoState, interval time.Duration, onChange func(Change) error) error {
+	for {
+		time.Sleep(interval)
+
+		current, err := GetRepoState(repoPath)
+		if err != nil {
+			// The repository can be briefly inconsistent mid-operation (e.g. during a rebase)
+			continue
+		}
+		if current == state {
+			continue
+		}
+
+		change := Change{From: state, To: current}
+		if current.Head != state.Head {
+			change.Changed, change.Deleted, err = GetChangedFiles(repoPath, state.Head, current.Head)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := onChange(change); err != nil {
+			return err
+		}
+		state = current
+	}
+}
+
+// UpdateAnalysis brings an analysis up to date with a change: changed files are
+// re-analyzed, deleted files dropped, and every other file's results reused, since a
+// file's scores depend only on its own history and the origin. That holds only while
+// opts.Origin equals the origin prev was computed against; when the origin moves
+// (e.g. --upstream was fetched), analyze the repository again instead. Results of
+// re-analyzed files pass through opts like a full analysis. Historical snapshots are
+// not carried over.
+func UpdateAnalysis(repoPath string, prev *models.CodebaseAnalysis, change Change, opts Options) (*models.CodebaseAnalysis, error) {
+	numWorkers := opts.NumWorkers
+	if numWorkers < 1 {
+		numWorkers = GetDefaultWorkerCount()
+	}
+
+	stale := make(map[string]bool, len(change.Changed)+len(change.Deleted))
+	for _, path := range change.Deleted {
+		stale[path] = true
+	}
+
+	var filesToAnalyze []string
+	for _, path := range change.Changed {
+		stale[path] = true
+		if !filter.ShouldSkipFile(path) {
+			filesToAnalyze = append(filesToAnalyze, path)
+		}
+	}
+
+	aggregator := NewAggregator()
+	for _, file := range prev.FileAnalyses {
+		if !stale[file.Path] {
+			aggregator.Consume(file)
+		}
+	}
+
+	if len(filesToAnalyze) > 0 {
+		fmt.Fprintf(os.Stderr, "Re-analyzing %d changed files with %d workers...\n\n", len(filesToAnalyze), numWorkers)
+		if err := processFilesParallel([]string{repoPath}, jobsFor(0, filesToAnalyze), numWorkers, opts.Origin, [][]ResultSink{opts.sinks(aggregator)}); err != nil {
+			return nil, err
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+
+	analysis := aggregator.Analysis()
+	analysis.Origin = opts.Origin.String()
+	return analysis, nil
+}
@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// WithWorktree checks out ref into a temporary, detached git worktree and calls fn
+// with its path. This lets any analysis run against a past commit without touching
+// the user's working directory. The worktree is removed when fn returns.
+func WithWorktree(repoPath, ref string, fn func(worktreePath string) error) error {
+	dir, remove, err := AddWorktree(repoPath, ref)
+	if err != nil {
+		return err
+	}
+	defer remove()
+
+	return fn(dir)
+}
+
+// AddWorktree checks out ref into a temporary, detached git worktree and returns its
+// path and a function that removes it, for callers that can't wrap their work in
+// WithWorktree.
+func AddWorktree(repoPath, ref string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "ship-of-theseus-worktree-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+
+	// Run: git -C <repo> worktree add --detach <dir> <ref>
+	cmd := exec.Command("git", "-C", repoPath, "worktree", "add", "--detach", dir, ref)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("git worktree add failed for %s: %s", ref, strings.TrimSpace(string(output)))
+	}
+
+	remove := func() {
+		exec.Command("git", "-C", repoPath, "worktree", "remove", "--force", dir).Run()
+		os.RemoveAll(dir)
+	}
+	return dir, remove, nil
+}
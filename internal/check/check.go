@@ -0,0 +1,208 @@
+// Package check evaluates originality quality gates against an analysis report.
+// Gates are expressed as rules: a minimum originality percentage and/or a maximum
+// drop relative to a baseline, applied to the whole repository or to a path pattern.
+package check
+
+import (
+	"fmt"
+	"regexp"
+	"ship-of-theseus/internal/report"
+	"strconv"
+	"strings"
+)
+
+// Rule is one quality gate. An empty Pattern applies the rule to the whole repository.
+type Rule struct {
+	Pattern     string  // Path glob ("internal/auth/**", "*.go") or directory prefix ("internal/auth/")
+	MinOriginal float64 // Minimum original percentage; ignored unless HasMin
+	HasMin      bool
+	MaxDrop     float64 // Maximum drop in percentage points versus the baseline; ignored unless HasMaxDrop
+	HasMaxDrop  bool
+}
+
+// Kind identifies which condition of a rule a Result evaluates.
+type Kind int
+
+const (
+	MinOriginal Kind = iota // Original percentage must be at least a threshold
+	MaxDrop                 // Original percentage may not fall more than a threshold below the baseline
+)
+
+// Result is the outcome of evaluating one condition of a rule.
+type Result struct {
+	Kind      Kind
+	Scope     string  // "overall" or the rule's path pattern
+	Condition string  // Human-readable condition, e.g. "original ≥ 40.0%"
+	Actual    float64 // Measured original percentage (or drop, for max-drop conditions)
+	Baseline  float64 // Baseline original percentage, for max-drop conditions
+	Files     int     // Number of files matched by the scope
+	Passed    bool
+	Message   string // Explanation when the condition could not be evaluated
+}
+
+// ParseRule parses a per-path rule of the form "<pattern>:min=80,max-drop=2".
+func ParseRule(spec string) (Rule, error) {
+	idx := strings.LastIndex(spec, ":")
+	if idx <= 0 || idx == len(spec)-1 {
+		return Rule{}, fmt.Errorf("invalid rule %q (expected <pattern>:min=N,max-drop=N)", spec)
+	}
+
+	rule := Rule{Pattern: spec[:idx]}
+	for _, part := range strings.Split(spec[idx+1:], ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("invalid rule condition %q in %q", part, spec)
+		}
+
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || n < 0 {
+			return Rule{}, fmt.Errorf("invalid value %q in rule %q", value, spec)
+		}
+
+		switch key {
+		case "min", "min-original":
+			rule.MinOriginal, rule.HasMin = n, true
+		case "max-drop":
+			rule.MaxDrop, rule.HasMaxDrop = n, true
+		default:
+			return Rule{}, fmt.Errorf("unknown rule condition %q in %q (use min or max-drop)", key, spec)
+		}
+	}
+
+	return rule, nil
+}
+
+// NeedsBaseline reports whether any rule compares against a baseline.
+func NeedsBaseline(rules []Rule) bool {
+	for _, rule := range rules {
+		if rule.HasMaxDrop {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate applies every rule to the current report. baseline may be nil when no
+// rule uses max-drop.
+func Evaluate(current, baseline *report.Report, rules []Rule) []Result {
+	var results []Result
+
+	for _, rule := range rules {
+		scope := rule.Pattern
+		if scope == "" {
+			scope = "overall"
+		}
+
+		pct, files := originalPct(current, rule.Pattern)
+
+		if rule.HasMin {
+			res := Result{
+				Kind:      MinOriginal,
+				Scope:     scope,
+				Condition: fmt.Sprintf("original ≥ %.1f%%", rule.MinOriginal),
+				Actual:    pct,
+				Files:     files,
+				Passed:    files > 0 && pct >= rule.MinOriginal,
+			}
+			if files == 0 {
+				res.Message = "no files matched"
+			}
+			results = append(results, res)
+		}
+
+		if rule.HasMaxDrop {
+			res := Result{
+				Kind:      MaxDrop,
+				Scope:     scope,
+				Condition: fmt.Sprintf("drop ≤ %.1f pts", rule.MaxDrop),
+				Files:     files,
+			}
+
+			basePct, baseFiles := originalPct(baseline, rule.Pattern)
+			switch {
+			case baseline == nil:
+				res.Message = "no baseline available"
+			case files == 0 || baseFiles == 0:
+				// Nothing to compare: a scope that is new (or gone) can't have dropped
+				res.Passed = true
+				res.Message = "not present in both baseline and current"
+			default:
+				res.Baseline = basePct
+				res.Actual = basePct - pct
+				res.Passed = res.Actual <= rule.MaxDrop
+			}
+			results = append(results, res)
+		}
+	}
+
+	return results
+}
+
+// Passed reports whether every result passed.
+func Passed(results []Result) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// originalPct computes the original percentage over the files matching pattern,
+// weighted by line count. An empty pattern uses the report's overall figure.
+func originalPct(r *report.Report, pattern string) (float64, int) {
+	if r == nil {
+		return 0, 0
+	}
+	if pattern == "" {
+		return r.OriginalPct, len(r.Files)
+	}
+
+	matcher := compilePattern(pattern)
+	total, original, files := 0, 0, 0
+	for _, f := range r.Files {
+		if matcher.MatchString(f.Path) {
+			total += f.TotalLines
+			original += f.OriginalLines
+			files++
+		}
+	}
+
+	if total == 0 {
+		return 0, files
+	}
+	return float64(original) / float64(total) * 100.0, files
+}
+
+// compilePattern converts a path pattern into a regular expression.
+// "**" matches across directories, "*" and "?" match within one path segment,
+// and a trailing "/" matches everything below a directory.
+func compilePattern(pattern string) *regexp.Regexp {
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			// "**/" matches zero or more whole directories
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,94 @@
+package check
+
+import "testing"
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Rule
+		wantErr bool
+	}{
+		{spec: "internal/auth/:min=90", want: Rule{Pattern: "internal/auth/", MinOriginal: 90, HasMin: true}},
+		{spec: "**/*.go:min-original=40", want: Rule{Pattern: "**/*.go", MinOriginal: 40, HasMin: true}},
+		{spec: "internal/crypto/**:max-drop=1.5", want: Rule{Pattern: "internal/crypto/**", MaxDrop: 1.5, HasMaxDrop: true}},
+		{
+			spec: "src/:min=50, max-drop=2",
+			want: Rule{Pattern: "src/", MinOriginal: 50, HasMin: true, MaxDrop: 2, HasMaxDrop: true},
+		},
+		{spec: "a:b/*.go:min=10", want: Rule{Pattern: "a:b/*.go", MinOriginal: 10, HasMin: true}},
+		{spec: "src/", wantErr: true},
+		{spec: ":min=10", wantErr: true},
+		{spec: "src/:", wantErr: true},
+		{spec: "src/:min", wantErr: true},
+		{spec: "src/:min=abc", wantErr: true},
+		{spec: "src/:min=-1", wantErr: true},
+		{spec: "src/:max=10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseRule(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRule(%q) = %+v, want an error", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRule(%q) failed: %v", tt.spec, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRule(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		// A trailing slash matches everything below the directory
+		{"internal/auth/", "internal/auth/login.go", true},
+		{"internal/auth/", "internal/auth/oauth/token.go", true},
+		{"internal/auth/", "internal/authz/policy.go", false},
+		{"internal/auth/", "internal/auth", false},
+
+		// "*" and "?" stay within one path segment
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"internal/*/handler.go", "internal/api/handler.go", true},
+		{"internal/*/handler.go", "internal/api/v1/handler.go", false},
+		{"file?.go", "file1.go", true},
+		{"file?.go", "file10.go", false},
+		{"file?.go", "dir/.go", false},
+
+		// "**/" matches zero or more whole directories
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "a/b/c/main.go", true},
+		{"**/*.go", "main.js", false},
+		{"src/**/test.go", "src/test.go", true},
+		{"src/**/test.go", "src/a/b/test.go", true},
+		{"src/**/test.go", "src/a/mytest.go", false},
+
+		// A trailing "**" matches anything below, across directories
+		{"internal/crypto/**", "internal/crypto/aes/gcm.go", true},
+		{"internal/crypto/**", "internal/cryptography/x.go", false},
+		{"docs**", "docs/guide/intro.md", true},
+
+		// Everything else is literal
+		{"main.go", "mainxgo", false},
+		{"a+b/(c).go", "a+b/(c).go", true},
+		{"src/[id].ts", "src/i.ts", false},
+		{"docs/é*.md", "docs/été.md", true},
+		{"docs/é*.md", "docs/ete.md", false},
+	}
+
+	for _, tt := range tests {
+		if got := compilePattern(tt.pattern).MatchString(tt.path); got != tt.want {
+			t.Errorf("compilePattern(%q) matching %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -23,6 +23,8 @@ var skipDirectories = map[string]bool{
 	"coverage":     true,
 	"tmp":          true,
 	"temp":         true,
+	// Ship of Theseus's own history store
+	".ship-of-theseus": true,
 }
 
 // binaryExtensions lists file extensions that indicate binary (non-text) files.
@@ -7,41 +7,102 @@ import "time"
 // CodebaseAnalysis represents the complete analysis results for a repository.
 // It aggregates statistics across all analyzed files and includes historical snapshots.
 type CodebaseAnalysis struct {
-	TotalLines          int             // Total lines of code analyzed (excluding comments, blanks)
-	OriginalLines       int             // Lines that are ≥25% similar to their first appearance
-	AverageSimilarity   float64         // Mean similarity across all lines (0.0 to 1.0)
-	FileAnalyses        []*FileAnalysis // Per-file detailed results
-	HistoricalSnapshots []Snapshot      // Timeline of code evolution
+	TotalLines          int             `json:"total_lines"`          // Total lines of code analyzed (excluding comments, blanks)
+	OriginalLines       int             `json:"original_lines"`       // Lines that are ≥25% similar to their first appearance
+	AverageSimilarity   float64         `json:"average_similarity"`   // Mean similarity across all lines (0.0 to 1.0)
+	FileAnalyses        []*FileAnalysis `json:"files"`                // Per-file detailed results
+	HistoricalSnapshots []Snapshot      `json:"historical_snapshots"` // Timeline of code evolution
+	Origin              string          `json:"origin,omitempty"`     // What lines were compared against, when not each file's first commit
 }
 
 // FileAnalysis represents the analysis results for a single file.
 // It contains line-by-line history tracing and aggregated file metrics.
 type FileAnalysis struct {
-	Path          string         // Relative path from repository root
-	TotalLines    int            // Total lines analyzed in this file
-	OriginalLines int            // Lines that are ≥25% similar to original
-	AvgSimilarity float64        // Mean similarity for this file's lines
-	LineHistories []*LineHistory // Detailed history for each line
+	Path          string         `json:"path"`                     // Relative path from repository root
+	TotalLines    int            `json:"total_lines"`              // Total lines analyzed in this file
+	OriginalLines int            `json:"original_lines"`           // Lines that are ≥25% similar to original
+	AvgSimilarity float64        `json:"avg_similarity"`           // Mean similarity for this file's lines
+	LineHistories []*LineHistory `json:"line_histories,omitempty"` // Detailed history for each line
 }
 
 // LineHistory traces a single line from its first appearance to current state.
 // It tracks the line's evolution through git history, measuring similarity.
 type LineHistory struct {
-	CurrentLine     string    // The line as it appears in current HEAD
-	OriginalLine    string    // The line as it first appeared in history
-	CurrentLineNum  int       // Line number in current file (1-indexed)
-	OriginalLineNum int       // Line number in original commit (1-indexed)
-	FirstCommitHash string    // Git commit hash where line first appeared
-	FirstCommitDate time.Time // Date of first commit
-	LastCommitHash  string    // Git commit hash of most recent modification
-	LastCommitDate  time.Time // Date of most recent modification
-	Similarity      float64   // Levenshtein similarity (0.0 to 1.0)
+	CurrentLine     string    `json:"current_line"`      // The line as it appears in current HEAD
+	OriginalLine    string    `json:"original_line"`     // The line as it first appeared in history
+	CurrentLineNum  int       `json:"current_line_num"`  // Line number in current file (1-indexed)
+	OriginalLineNum int       `json:"original_line_num"` // Line number in original commit (1-indexed)
+	FirstCommitHash string    `json:"first_commit"`      // Git commit hash where line first appeared
+	FirstCommitDate time.Time `json:"first_commit_date"` // Date of first commit
+	LastCommitHash  string    `json:"last_commit"`       // Git commit hash of most recent modification
+	LastCommitDate  time.Time `json:"last_commit_date"`  // Date of most recent modification
+	Similarity      float64   `json:"similarity"`        // Levenshtein similarity (0.0 to 1.0)
 }
 
 // Snapshot represents the estimated state of the codebase at a point in history.
 // Used to generate the evolution timeline graph showing how originality changes over time.
 type Snapshot struct {
-	CommitHash  string    // Git commit hash for this snapshot
-	Date        time.Time // Commit date
-	OriginalPct float64   // Estimated percentage of original code remaining
+	CommitHash  string    `json:"commit"`          // Git commit hash for this snapshot
+	Date        time.Time `json:"date"`            // Commit date
+	OriginalPct float64   `json:"original_pct"`    // Estimated percentage of original code remaining
+	Label       string    `json:"label,omitempty"` // Axis label in place of the date, e.g. a release tag
+
+	// Size is measured two ways. Lines counts every line of the analyzed files,
+	// comments and blanks included, from git diffs; it is cheap, so every snapshot
+	// has it. TotalLines counts code lines the way the analysis does, which takes
+	// reading every file, so only snapshots that were analyzed have it. The two
+	// differ by the comments and blanks, and mixing them on one chart would show
+	// that difference as a jump.
+	Lines         int `json:"lines,omitempty"`          // Lines in analyzed files counted from git history, including comments and blanks
+	TotalLines    int `json:"total_lines,omitempty"`    // Lines of code as the analysis counts them; only on measured snapshots
+	OriginalLines int `json:"original_lines,omitempty"` // Original lines as the analysis counts them; only on measured snapshots
+	LinesAdded    int `json:"lines_added,omitempty"`    // Lines added to analyzed files by the commits since the previous snapshot, summed per commit
+	LinesRemoved  int `json:"lines_removed,omitempty"`  // Lines removed from analyzed files by the commits since the previous snapshot, summed per commit
+	Contributors  int `json:"contributors,omitempty"`   // Distinct authors of the commits up to this snapshot
+}
+
+// FileChurn summarizes how much a file has changed over the commits of a history.
+type FileChurn struct {
+	Commits      int `json:"commits"`       // Commits that changed the file
+	LinesAdded   int `json:"lines_added"`   // Lines added across those commits
+	LinesRemoved int `json:"lines_removed"` // Lines removed across those commits
+}
+
+// CoChanges counts how often files changed, on their own and in the same commit as
+// each other file.
+type CoChanges struct {
+	Commits int              // Commits counted
+	Skipped int              // Commits left out for changing too many files at once
+	Files   map[string]int   // Commits that changed each file
+	Pairs   map[FilePair]int // Commits that changed both files of a pair
+}
+
+// FilePair is two files, in path order.
+type FilePair struct {
+	A, B string
+}
+
+// LineExplanation records each step of a single line's classification, so a
+// surprising score can be traced back to the comparison that produced it.
+type LineExplanation struct {
+	FilePath       string
+	LineNum        int          // Line number in current file (1-indexed)
+	OriginName     string       // The origin every file is compared with, when not each file's first commit
+	FileCommits    []string     // File history from git log --follow, newest first
+	FirstCommit    string       // Commit whose version of the file is the origin
+	WindowStart    int          // First line of the search window in the original version (1-indexed)
+	WindowEnd      int          // Last line of the search window (inclusive)
+	WindowRadius   int          // How far (±lines) a line may have moved and still match
+	Candidates     []Candidate  // Every line in the window with its similarity to the current line
+	Threshold      float64      // Minimum similarity for a candidate to match, and for a line to count as original
+	Result         *LineHistory // The classification, exactly as the analysis computes it
+	Original       bool         // Whether Result counts as original
+	FallbackReason string       // Why the origin search was skipped, if it was
+}
+
+// Candidate is one line of the original version considered as the origin of a current line.
+type Candidate struct {
+	LineNum    int // Line number in the original version (1-indexed)
+	Line       string
+	Similarity float64
 }
@@ -0,0 +1,88 @@
+package report
+
+import (
+	"ship-of-theseus/internal/models"
+	"sort"
+	"time"
+)
+
+// Couplings lists the pairs of files that most often change together, which points
+// at dependencies the code itself doesn't show.
+type Couplings struct {
+	FormatVersion int        `json:"format_version"`
+	GeneratedAt   time.Time  `json:"generated_at"`
+	CommitHash    string     `json:"commit,omitempty"` // Commit the originality was measured at, if it was
+	Commits       int        `json:"commits"`          // Commits whose changes were counted
+	Skipped       int        `json:"skipped_commits"`  // Commits left out for changing too many files at once
+	Pairs         []Coupling `json:"pairs"`            // Strongest first
+}
+
+// Coupling is how often two files changed in the same commit.
+type Coupling struct {
+	A      CoupledFile `json:"a"`
+	B      CoupledFile `json:"b"`
+	Shared int         `json:"shared_commits"` // Commits that changed both files
+	Degree float64     `json:"degree"`         // Shared commits as a percentage of the average of both files' commits
+}
+
+// CoupledFile is one file of a coupled pair.
+type CoupledFile struct {
+	Path        string   `json:"path"`
+	Commits     int      `json:"commits"`                // Commits that changed the file
+	Confidence  float64  `json:"confidence"`             // Percentage of those commits that also changed the other file
+	OriginalPct *float64 `json:"original_pct,omitempty"` // Current originality, when it was measured
+}
+
+// NewCouplings ranks the pairs of co-changes that share at least minShared commits,
+// by degree and then by shared commits. When originality (by path) is given, it is
+// recorded for both files of each pair.
+func NewCouplings(co *models.CoChanges, minShared int, originality map[string]float64) *Couplings {
+	c := &Couplings{
+		FormatVersion: FormatVersion,
+		GeneratedAt:   time.Now().UTC(),
+		Commits:       co.Commits,
+		Skipped:       co.Skipped,
+		Pairs:         []Coupling{},
+	}
+
+	file := func(path string, shared int) CoupledFile {
+		f := CoupledFile{
+			Path:       path,
+			Commits:    co.Files[path],
+			Confidence: float64(shared) / float64(co.Files[path]) * 100.0,
+		}
+		if pct, ok := originality[path]; ok {
+			f.OriginalPct = &pct
+		}
+		return f
+	}
+
+	for pair, shared := range co.Pairs {
+		if shared < minShared {
+			continue
+		}
+		a, b := file(pair.A, shared), file(pair.B, shared)
+		c.Pairs = append(c.Pairs, Coupling{
+			A:      a,
+			B:      b,
+			Shared: shared,
+			Degree: float64(shared) / (float64(a.Commits+b.Commits) / 2) * 100.0,
+		})
+	}
+
+	sort.Slice(c.Pairs, func(i, j int) bool {
+		p, q := c.Pairs[i], c.Pairs[j]
+		switch {
+		case p.Degree != q.Degree:
+			return p.Degree > q.Degree
+		case p.Shared != q.Shared:
+			return p.Shared > q.Shared
+		case p.A.Path != q.A.Path:
+			return p.A.Path < q.A.Path
+		default:
+			return p.B.Path < q.B.Path
+		}
+	})
+
+	return c
+}
@@ -0,0 +1,60 @@
+package report
+
+import "sort"
+
+// FileDelta is one file whose originality differs between two reports.
+type FileDelta struct {
+	Path string
+	Base *FileSummary // nil if the file was added
+	Head *FileSummary // nil if the file was removed
+}
+
+// OriginalPct returns the file's original percentage in the base and head reports
+// (zero where the file is absent).
+func (d FileDelta) OriginalPct() (base, head float64) {
+	return summaryPct(d.Base), summaryPct(d.Head)
+}
+
+// Diff compares two reports.
+type Diff struct {
+	Base  *Report
+	Head  *Report
+	Files []FileDelta // Changed, added and removed files, sorted by path
+}
+
+// Compare returns the difference between two reports. Files present in both with
+// identical line counts are omitted.
+func Compare(base, head *Report) *Diff {
+	d := &Diff{Base: base, Head: head}
+
+	baseFiles := make(map[string]*FileSummary, len(base.Files))
+	for i := range base.Files {
+		baseFiles[base.Files[i].Path] = &base.Files[i]
+	}
+
+	for i := range head.Files {
+		h := &head.Files[i]
+		b, ok := baseFiles[h.Path]
+		delete(baseFiles, h.Path)
+		if ok && b.TotalLines == h.TotalLines && b.OriginalLines == h.OriginalLines {
+			continue
+		}
+		d.Files = append(d.Files, FileDelta{Path: h.Path, Base: b, Head: h})
+	}
+
+	for path, b := range baseFiles {
+		d.Files = append(d.Files, FileDelta{Path: path, Base: b})
+	}
+
+	sort.Slice(d.Files, func(i, j int) bool {
+		return d.Files[i].Path < d.Files[j].Path
+	})
+	return d
+}
+
+func summaryPct(f *FileSummary) float64 {
+	if f == nil || f.TotalLines == 0 {
+		return 0
+	}
+	return float64(f.OriginalLines) / float64(f.TotalLines) * 100.0
+}
@@ -0,0 +1,43 @@
+package report
+
+import "time"
+
+// Fleet combines the reports of several repositories, with totals across all of them.
+type Fleet struct {
+	FormatVersion     int         `json:"format_version"`
+	GeneratedAt       time.Time   `json:"generated_at"`
+	TotalLines        int         `json:"total_lines"`
+	OriginalLines     int         `json:"original_lines"`
+	OriginalPct       float64     `json:"original_pct"`
+	AverageSimilarity float64     `json:"average_similarity"` // Weighted by lines, like a single report
+	Repos             []FleetRepo `json:"repos"`
+}
+
+// FleetRepo is one repository of a fleet.
+type FleetRepo struct {
+	Name   string  `json:"name"`
+	Path   string  `json:"path"`
+	Report *Report `json:"report"`
+}
+
+// NewFleet totals the reports of several repositories. Repos keep the given order.
+func NewFleet(repos []FleetRepo) *Fleet {
+	f := &Fleet{
+		FormatVersion: FormatVersion,
+		GeneratedAt:   time.Now().UTC(),
+		Repos:         repos,
+	}
+
+	totalSimilarity := 0.0
+	for _, repo := range repos {
+		f.TotalLines += repo.Report.TotalLines
+		f.OriginalLines += repo.Report.OriginalLines
+		totalSimilarity += repo.Report.AverageSimilarity * float64(repo.Report.TotalLines)
+	}
+	if f.TotalLines > 0 {
+		f.OriginalPct = float64(f.OriginalLines) / float64(f.TotalLines) * 100.0
+		f.AverageSimilarity = totalSimilarity / float64(f.TotalLines)
+	}
+
+	return f
+}
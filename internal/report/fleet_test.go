@@ -0,0 +1,33 @@
+package report
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewFleet(t *testing.T) {
+	fleet := NewFleet([]FleetRepo{
+		{Name: "api", Report: &Report{TotalLines: 300, OriginalLines: 150, AverageSimilarity: 0.5}},
+		{Name: "web", Report: &Report{TotalLines: 100, OriginalLines: 90, AverageSimilarity: 0.9}},
+		{Name: "empty", Report: &Report{}},
+	})
+
+	if fleet.TotalLines != 400 || fleet.OriginalLines != 240 {
+		t.Errorf("fleet totals = %d lines, %d original, want 400 and 240", fleet.TotalLines, fleet.OriginalLines)
+	}
+	if fleet.OriginalPct != 60 {
+		t.Errorf("OriginalPct = %v, want 60", fleet.OriginalPct)
+	}
+	// Weighted by lines: (300×0.5 + 100×0.9) / 400
+	if math.Abs(fleet.AverageSimilarity-0.6) > 1e-9 {
+		t.Errorf("AverageSimilarity = %v, want 0.6", fleet.AverageSimilarity)
+	}
+	if len(fleet.Repos) != 3 || fleet.FormatVersion != FormatVersion {
+		t.Errorf("fleet = %+v, want all three repositories at the current format version", fleet)
+	}
+
+	empty := NewFleet(nil)
+	if empty.TotalLines != 0 || empty.OriginalPct != 0 || empty.AverageSimilarity != 0 {
+		t.Errorf("empty fleet = %+v, want zero totals", empty)
+	}
+}
@@ -0,0 +1,130 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"ship-of-theseus/internal/models"
+	"sort"
+	"time"
+)
+
+// DefaultHistoryPath is where the history store lives, relative to the repository root.
+const DefaultHistoryPath = ".ship-of-theseus/history.jsonl"
+
+// History is an append-only store of past reports, one JSON object per line.
+// Entries are keyed by commit hash: when a commit is analyzed more than once,
+// the most recently generated entry wins on read, but nothing is ever rewritten.
+type History struct {
+	path string
+}
+
+// OpenHistory returns the history store at path. The file is created on first Append.
+func OpenHistory(path string) *History {
+	return &History{path: path}
+}
+
+// Path returns the location of the history file.
+func (h *History) Path() string {
+	return h.path
+}
+
+// Append records a report. Per-file detail is dropped to keep the store compact.
+func (h *History) Append(r *Report) error {
+	data, err := json.Marshal(r.Compact())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load returns one report per commit (the latest generated), ordered by commit date.
+// A missing history file yields no entries rather than an error.
+func (h *History) Load() ([]*Report, error) {
+	f, err := os.Open(h.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	latest := make(map[string]*Report)
+	var unkeyed []*Report
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var r Report
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid history entry: %w", h.path, lineNum, err)
+		}
+
+		if r.CommitHash == "" {
+			unkeyed = append(unkeyed, &r)
+			continue
+		}
+		if prev, ok := latest[r.CommitHash]; !ok || r.GeneratedAt.After(prev.GeneratedAt) {
+			latest[r.CommitHash] = &r
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", h.path, err)
+	}
+
+	reports := unkeyed
+	for _, r := range latest {
+		reports = append(reports, r)
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reportTime(reports[i]).Before(reportTime(reports[j]))
+	})
+
+	return reports, nil
+}
+
+// ToSnapshots converts stored reports into timeline snapshots of measured values.
+func ToSnapshots(reports []*Report) []models.Snapshot {
+	snapshots := make([]models.Snapshot, 0, len(reports))
+	for _, r := range reports {
+		snapshots = append(snapshots, models.Snapshot{
+			CommitHash:    r.CommitHash,
+			Date:          reportTime(r),
+			OriginalPct:   r.OriginalPct,
+			TotalLines:    r.TotalLines,
+			OriginalLines: r.OriginalLines,
+		})
+	}
+	return snapshots
+}
+
+// reportTime orders reports by commit date, falling back to when they were generated.
+func reportTime(r *Report) time.Time {
+	if r.CommitDate != nil {
+		return *r.CommitDate
+	}
+	return r.GeneratedAt
+}
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"ship-of-theseus/internal/models"
+	"sort"
+	"time"
+)
+
+// Hotspots ranks the files of a report that are both heavily churned and largely
+// rewritten, the likeliest candidates for refactoring or better tests.
+type Hotspots struct {
+	FormatVersion int       `json:"format_version"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	CommitHash    string    `json:"commit,omitempty"` // Commit the originality was measured at
+	Commits       int       `json:"commits"`          // Commits whose changes were counted
+	Files         []Hotspot `json:"files"`            // Highest score first
+}
+
+// Hotspot is one file's churn alongside its current originality and size.
+type Hotspot struct {
+	Path          string  `json:"path"`
+	Commits       int     `json:"commits"`       // Commits that changed the file
+	LinesAdded    int     `json:"lines_added"`   // Lines added across those commits
+	LinesRemoved  int     `json:"lines_removed"` // Lines removed across those commits
+	TotalLines    int     `json:"total_lines"`
+	OriginalLines int     `json:"original_lines"`
+	OriginalPct   float64 `json:"original_pct"`
+	Score         float64 `json:"score"` // 0-100; see NewHotspots
+}
+
+// NewHotspots combines the files of a report with their churn. A file's score is its
+// churn relative to the most churned file (commits and lines changed, weighted
+// equally) times the share of it that has been rewritten, scaled to 0-100: a file
+// scores high only when it changes often and little of its original code is left.
+// Files that no longer exist or never changed are left out.
+func NewHotspots(r *Report, churn map[string]models.FileChurn, commits int) *Hotspots {
+	h := &Hotspots{
+		FormatVersion: FormatVersion,
+		GeneratedAt:   time.Now().UTC(),
+		CommitHash:    r.CommitHash,
+		Commits:       commits,
+		Files:         []Hotspot{},
+	}
+
+	maxCommits, maxChanged := 0, 0
+	for _, f := range r.Files {
+		if c, ok := churn[f.Path]; ok && f.TotalLines > 0 {
+			h.Files = append(h.Files, Hotspot{
+				Path:          f.Path,
+				Commits:       c.Commits,
+				LinesAdded:    c.LinesAdded,
+				LinesRemoved:  c.LinesRemoved,
+				TotalLines:    f.TotalLines,
+				OriginalLines: f.OriginalLines,
+				OriginalPct:   float64(f.OriginalLines) / float64(f.TotalLines) * 100.0,
+			})
+			maxCommits = max(maxCommits, c.Commits)
+			maxChanged = max(maxChanged, c.LinesAdded+c.LinesRemoved)
+		}
+	}
+
+	for i := range h.Files {
+		f := &h.Files[i]
+		activity := float64(f.Commits) / float64(maxCommits) / 2
+		if maxChanged > 0 {
+			activity += float64(f.LinesAdded+f.LinesRemoved) / float64(maxChanged) / 2
+		}
+		f.Score = activity * (100.0 - f.OriginalPct)
+	}
+
+	sort.SliceStable(h.Files, func(i, j int) bool {
+		a, b := h.Files[i], h.Files[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Commits > b.Commits
+	})
+
+	return h
+}
@@ -0,0 +1,39 @@
+package report
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// MarshalNote encodes the report as the body of a git note: compact JSON on one line,
+// without per-file detail.
+func (r *Report) MarshalNote() (string, error) {
+	data, err := json.Marshal(r.Compact())
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// FromNotes decodes git notes (keyed by commit hash) into reports ordered by commit date.
+// Notes that aren't Ship of Theseus summaries are skipped; the number skipped is returned.
+func FromNotes(notes map[string]string) ([]*Report, int) {
+	var reports []*Report
+	skipped := 0
+
+	for commit, body := range notes {
+		var r Report
+		if err := json.Unmarshal([]byte(body), &r); err != nil || r.FormatVersion == 0 {
+			skipped++
+			continue
+		}
+		// The annotated commit is authoritative, even if the note was copied elsewhere
+		r.CommitHash = commit
+		reports = append(reports, &r)
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reportTime(reports[i]).Before(reportTime(reports[j]))
+	})
+	return reports, skipped
+}
@@ -0,0 +1,115 @@
+// Package report defines the compact, serializable summary of an analysis run.
+// Reports drop line-level detail so they stay small enough to commit, compare
+// against in CI, or store one per analyzed commit.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"ship-of-theseus/internal/models"
+	"sort"
+	"time"
+)
+
+// FormatVersion is incremented whenever the report layout changes incompatibly.
+const FormatVersion = 1
+
+// Report is the compact summary of one analysis.
+type Report struct {
+	FormatVersion     int               `json:"format_version"`
+	ToolVersion       string            `json:"tool_version,omitempty"` // Version of ship-of-theseus that produced the report
+	CommitHash        string            `json:"commit,omitempty"`       // Analyzed commit
+	CommitDate        *time.Time        `json:"commit_date,omitempty"`  // Date of the analyzed commit
+	GeneratedAt       time.Time         `json:"generated_at"`           // When the analysis ran
+	TotalLines        int               `json:"total_lines"`
+	OriginalLines     int               `json:"original_lines"`
+	OriginalPct       float64           `json:"original_pct"`
+	AverageSimilarity float64           `json:"average_similarity"`
+	Settings          *Settings         `json:"settings,omitempty"` // Analysis settings that affect the numbers
+	Files             []FileSummary     `json:"files,omitempty"`    // Omitted in history entries and notes
+	Timeline          []models.Snapshot `json:"timeline,omitempty"` // Omitted in history entries and notes
+}
+
+// Settings records the analysis parameters behind a report, so that runs made with
+// different settings aren't mistaken for a change in the codebase.
+type Settings struct {
+	SimilarityThreshold float64 `json:"similarity_threshold"`
+	LineMovementWindow  int     `json:"line_movement_window"`
+	SampleRate          int     `json:"sample_rate,omitempty"`
+	Origin              string  `json:"origin,omitempty"` // What lines were compared against, when not each file's first commit
+}
+
+// FileSummary is the per-file portion of a report.
+type FileSummary struct {
+	Path          string  `json:"path"`
+	TotalLines    int     `json:"total_lines"`
+	OriginalLines int     `json:"original_lines"`
+	AvgSimilarity float64 `json:"avg_similarity"`
+}
+
+// New summarizes an analysis. Files are sorted by path so reports diff cleanly.
+func New(analysis *models.CodebaseAnalysis) *Report {
+	r := &Report{
+		FormatVersion:     FormatVersion,
+		GeneratedAt:       time.Now().UTC(),
+		TotalLines:        analysis.TotalLines,
+		OriginalLines:     analysis.OriginalLines,
+		AverageSimilarity: analysis.AverageSimilarity,
+		Files:             make([]FileSummary, 0, len(analysis.FileAnalyses)),
+		Timeline:          analysis.HistoricalSnapshots,
+	}
+	if analysis.TotalLines > 0 {
+		r.OriginalPct = float64(analysis.OriginalLines) / float64(analysis.TotalLines) * 100.0
+	}
+
+	for _, fa := range analysis.FileAnalyses {
+		r.Files = append(r.Files, FileSummary{
+			Path:          fa.Path,
+			TotalLines:    fa.TotalLines,
+			OriginalLines: fa.OriginalLines,
+			AvgSimilarity: fa.AvgSimilarity,
+		})
+	}
+	sort.Slice(r.Files, func(i, j int) bool {
+		return r.Files[i].Path < r.Files[j].Path
+	})
+
+	return r
+}
+
+// Load reads a report from a JSON file.
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid report %s: %w", path, err)
+	}
+	if r.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("report %s has format version %d; this build understands up to %d",
+			path, r.FormatVersion, FormatVersion)
+	}
+
+	return &r, nil
+}
+
+// Compact returns a copy of the report without per-file detail or timeline.
+func (r *Report) Compact() *Report {
+	c := *r
+	c.Files = nil
+	c.Timeline = nil
+	return &c
+}
+
+// Save writes the report as indented JSON.
+func (r *Report) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
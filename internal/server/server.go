@@ -0,0 +1,226 @@
+// Package server exposes analysis results over HTTP: a small JSON API and an
+// embedded web UI that browses it.
+package server
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"ship-of-theseus/internal/models"
+	"ship-of-theseus/internal/report"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed static
+var static embed.FS
+
+// ErrNoLines is returned by a LineLoader when line-level detail isn't available.
+var ErrNoLines = errors.New("line-level detail is not available")
+
+// LineLoader returns the line-level detail of a file.
+type LineLoader func(path string) ([]*models.LineHistory, error)
+
+// Data is the analysis the server exposes.
+type Data struct {
+	Report   *report.Report    // Totals and per-file summaries
+	Timeline []models.Snapshot // May be empty
+	Lines    LineLoader        // Nil when serving a saved report without line detail
+}
+
+// Server serves one Data, which can be replaced while it runs.
+type Server struct {
+	mu    sync.RWMutex
+	data  *Data
+	files map[string]bool // Paths of data's analyzed files, which may be in any order
+	mux   *http.ServeMux
+}
+
+// New returns a server for data.
+func New(data *Data) *Server {
+	s := &Server{data: data, files: indexFiles(data), mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /api/summary", s.handleSummary)
+	s.mux.HandleFunc("GET /api/files", s.handleFiles)
+	s.mux.HandleFunc("GET /api/files/{path...}", s.handleFileLines)
+	s.mux.HandleFunc("GET /api/timeline", s.handleTimeline)
+
+	ui, _ := fs.Sub(static, "static")
+	s.mux.Handle("GET /", http.FileServer(http.FS(ui)))
+
+	return s
+}
+
+// Update replaces the served data; requests in flight finish with the old data.
+func (s *Server) Update(data *Data) {
+	files := indexFiles(data)
+	s.mu.Lock()
+	s.data, s.files = data, files
+	s.mu.Unlock()
+}
+
+func (s *Server) current() *Data {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data
+}
+
+// currentFiles returns the served data together with the paths of its files.
+func (s *Server) currentFiles() (*Data, map[string]bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data, s.files
+}
+
+// indexFiles collects the paths of the files in data's report.
+func indexFiles(data *Data) map[string]bool {
+	if data == nil {
+		return nil
+	}
+	files := make(map[string]bool, len(data.Report.Files))
+	for _, f := range data.Report.Files {
+		files[f.Path] = true
+	}
+	return files
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleSummary serves the report totals without per-file detail.
+func (s *Server) handleSummary(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.current().Report.Compact())
+}
+
+// handleFiles serves per-file summaries. Query parameters:
+//
+//	prefix  only files whose path starts with this
+//	sort    path (default), originality, similarity or size
+//	order   asc or desc (default: asc, except desc for size)
+//	limit   at most this many files
+func (s *Server) handleFiles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var files []report.FileSummary
+	for _, f := range s.current().Report.Files {
+		if strings.HasPrefix(f.Path, q.Get("prefix")) {
+			files = append(files, f)
+		}
+	}
+
+	var less func(a, b report.FileSummary) bool
+	descending := false
+	switch q.Get("sort") {
+	case "", "path":
+		less = func(a, b report.FileSummary) bool { return a.Path < b.Path }
+	case "originality":
+		less = func(a, b report.FileSummary) bool { return filePct(a) < filePct(b) }
+	case "similarity":
+		less = func(a, b report.FileSummary) bool { return a.AvgSimilarity < b.AvgSimilarity }
+	case "size":
+		less = func(a, b report.FileSummary) bool { return a.TotalLines < b.TotalLines }
+		descending = true
+	default:
+		writeError(w, http.StatusBadRequest, "sort must be one of: path, originality, similarity, size")
+		return
+	}
+
+	switch q.Get("order") {
+	case "":
+	case "asc":
+		descending = false
+	case "desc":
+		descending = true
+	default:
+		writeError(w, http.StatusBadRequest, "order must be asc or desc")
+		return
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if descending {
+			return less(files[j], files[i])
+		}
+		return less(files[i], files[j])
+	})
+
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		files = files[:min(n, len(files))]
+	}
+
+	if files == nil {
+		files = []report.FileSummary{}
+	}
+	writeJSON(w, http.StatusOK, files)
+}
+
+// handleFileLines serves /api/files/{path}/lines: a file's line-level detail.
+func (s *Server) handleFileLines(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutSuffix(r.PathValue("path"), "/lines")
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found; use /api/files/{path}/lines")
+		return
+	}
+
+	data, files := s.currentFiles()
+	if !files[path] {
+		writeError(w, http.StatusNotFound, "no analyzed file "+path)
+		return
+	}
+
+	if data.Lines == nil {
+		writeError(w, http.StatusNotFound, ErrNoLines.Error())
+		return
+	}
+
+	lines, err := data.Lines(path)
+	if errors.Is(err, ErrNoLines) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if lines == nil {
+		lines = []*models.LineHistory{}
+	}
+	writeJSON(w, http.StatusOK, lines)
+}
+
+// handleTimeline serves the historical snapshots, oldest first.
+func (s *Server) handleTimeline(w http.ResponseWriter, r *http.Request) {
+	timeline := s.current().Timeline
+	if timeline == nil {
+		timeline = []models.Snapshot{}
+	}
+	writeJSON(w, http.StatusOK, timeline)
+}
+
+func filePct(f report.FileSummary) float64 {
+	if f.TotalLines == 0 {
+		return 0
+	}
+	return float64(f.OriginalLines) / float64(f.TotalLines) * 100.0
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
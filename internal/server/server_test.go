@@ -0,0 +1,229 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"ship-of-theseus/internal/models"
+	"ship-of-theseus/internal/report"
+	"testing"
+	"time"
+)
+
+// testData returns a report whose files are deliberately not sorted by path, as in a
+// hand-edited report or one written by another tool.
+func testData() *Data {
+	return &Data{
+		Report: &report.Report{
+			FormatVersion: report.FormatVersion,
+			TotalLines:    400,
+			OriginalLines: 220,
+			OriginalPct:   55,
+			Files: []report.FileSummary{
+				{Path: "internal/b/big.go", TotalLines: 200, OriginalLines: 40, AvgSimilarity: 0.3},
+				{Path: "cmd/main.go", TotalLines: 50, OriginalLines: 50, AvgSimilarity: 1.0},
+				{Path: "internal/a/small.go", TotalLines: 10, OriginalLines: 5, AvgSimilarity: 0.6},
+				{Path: "internal/a/mid.go", TotalLines: 140, OriginalLines: 125, AvgSimilarity: 0.9},
+			},
+		},
+		Timeline: []models.Snapshot{
+			{CommitHash: "aaa", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), OriginalPct: 90},
+			{CommitHash: "bbb", Date: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), OriginalPct: 55},
+		},
+	}
+}
+
+// get requests target from the server and decodes a JSON response into v.
+func get(t *testing.T, s *Server, target string, v any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("GET %s: Content-Type %q, want application/json", target, ct)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("GET %s: invalid JSON %q: %v", target, rec.Body.String(), err)
+	}
+	return rec.Code
+}
+
+func TestSummary(t *testing.T) {
+	s := New(testData())
+
+	var got map[string]any
+	if code := get(t, s, "/api/summary", &got); code != http.StatusOK {
+		t.Fatalf("status %d, want 200", code)
+	}
+	if got["total_lines"] != 400.0 || got["original_pct"] != 55.0 {
+		t.Errorf("summary = %v, want the report totals", got)
+	}
+	if _, ok := got["files"]; ok {
+		t.Errorf("summary includes per-file detail: %v", got["files"])
+	}
+}
+
+func TestFiles(t *testing.T) {
+	s := New(testData())
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"/api/files", []string{"cmd/main.go", "internal/a/mid.go", "internal/a/small.go", "internal/b/big.go"}},
+		{"/api/files?sort=path&order=desc", []string{"internal/b/big.go", "internal/a/small.go", "internal/a/mid.go", "cmd/main.go"}},
+		{"/api/files?sort=originality", []string{"internal/b/big.go", "internal/a/small.go", "internal/a/mid.go", "cmd/main.go"}},
+		{"/api/files?sort=similarity&order=desc", []string{"cmd/main.go", "internal/a/mid.go", "internal/a/small.go", "internal/b/big.go"}},
+		{"/api/files?sort=size", []string{"internal/b/big.go", "internal/a/mid.go", "cmd/main.go", "internal/a/small.go"}},
+		{"/api/files?sort=size&order=asc", []string{"internal/a/small.go", "cmd/main.go", "internal/a/mid.go", "internal/b/big.go"}},
+		{"/api/files?prefix=internal/a/", []string{"internal/a/mid.go", "internal/a/small.go"}},
+		{"/api/files?sort=size&limit=2", []string{"internal/b/big.go", "internal/a/mid.go"}},
+		{"/api/files?limit=10", []string{"cmd/main.go", "internal/a/mid.go", "internal/a/small.go", "internal/b/big.go"}},
+		{"/api/files?limit=0", []string{}},
+		{"/api/files?prefix=docs/", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			var files []report.FileSummary
+			if code := get(t, s, tt.target, &files); code != http.StatusOK {
+				t.Fatalf("status %d, want 200", code)
+			}
+			got := []string{}
+			for _, f := range files {
+				got = append(got, f.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilesBadRequest(t *testing.T) {
+	s := New(testData())
+
+	for _, target := range []string{
+		"/api/files?sort=name",
+		"/api/files?order=up",
+		"/api/files?limit=-1",
+		"/api/files?limit=ten",
+	} {
+		t.Run(target, func(t *testing.T) {
+			var got map[string]string
+			if code := get(t, s, target, &got); code != http.StatusBadRequest {
+				t.Errorf("status %d, want 400", code)
+			}
+			if got["error"] == "" {
+				t.Errorf("response %v has no error message", got)
+			}
+		})
+	}
+}
+
+func TestFileLines(t *testing.T) {
+	data := testData()
+	var requested []string
+	data.Lines = func(path string) ([]*models.LineHistory, error) {
+		requested = append(requested, path)
+		switch path {
+		case "cmd/main.go":
+			return nil, ErrNoLines
+		case "internal/a/small.go":
+			return nil, errors.New("spool unreadable")
+		}
+		return []*models.LineHistory{{CurrentLine: "package " + path, CurrentLineNum: 1, Similarity: 1}}, nil
+	}
+	s := New(data)
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		// Nested paths, found whatever the order of the report's files
+		{"/api/files/internal/b/big.go/lines", http.StatusOK},
+		{"/api/files/internal/a/mid.go/lines", http.StatusOK},
+		{"/api/files/cmd/main.go/lines", http.StatusNotFound}, // ErrNoLines
+		{"/api/files/internal/a/small.go/lines", http.StatusInternalServerError},
+		{"/api/files/internal/c/missing.go/lines", http.StatusNotFound}, // Not analyzed
+		{"/api/files/internal/b/big.go", http.StatusNotFound},           // No /lines suffix
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			var got any
+			if code := get(t, s, tt.target, &got); code != tt.status {
+				t.Errorf("status %d, want %d (%v)", code, tt.status, got)
+			}
+		})
+	}
+
+	var lines []*models.LineHistory
+	get(t, s, "/api/files/internal/b/big.go/lines", &lines)
+	if len(lines) != 1 || lines[0].CurrentLine != "package internal/b/big.go" {
+		t.Errorf("lines = %+v, want the loader's lines for internal/b/big.go", lines)
+	}
+
+	// Only analyzed files reach the loader
+	for _, path := range requested {
+		if path == "internal/c/missing.go" {
+			t.Errorf("loader called for a file that wasn't analyzed")
+		}
+	}
+}
+
+func TestFileLinesWithoutLoader(t *testing.T) {
+	s := New(testData())
+
+	var got map[string]string
+	if code := get(t, s, "/api/files/internal/b/big.go/lines", &got); code != http.StatusNotFound {
+		t.Errorf("status %d, want 404", code)
+	}
+	if got["error"] != ErrNoLines.Error() {
+		t.Errorf("error = %q, want %q", got["error"], ErrNoLines.Error())
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	s := New(testData())
+
+	var timeline []models.Snapshot
+	if code := get(t, s, "/api/timeline", &timeline); code != http.StatusOK {
+		t.Fatalf("status %d, want 200", code)
+	}
+	if len(timeline) != 2 || timeline[0].CommitHash != "aaa" || timeline[1].OriginalPct != 55 {
+		t.Errorf("timeline = %+v, want the two snapshots oldest first", timeline)
+	}
+
+	// An empty timeline is an empty array, not null
+	data := testData()
+	data.Timeline = nil
+	s.Update(data)
+	var raw json.RawMessage
+	get(t, s, "/api/timeline", &raw)
+	if string(raw) != "[]" {
+		t.Errorf("empty timeline = %s, want []", raw)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	// Data may arrive after the server is created
+	s := New(nil)
+	s.Update(testData())
+
+	data := testData()
+	data.Report.Files = data.Report.Files[:1]
+	s.Update(data)
+
+	var files []report.FileSummary
+	get(t, s, "/api/files", &files)
+	if len(files) != 1 {
+		t.Errorf("after Update, %d files served, want 1", len(files))
+	}
+
+	var got map[string]string
+	if code := get(t, s, "/api/files/cmd/main.go/lines", &got); code != http.StatusNotFound {
+		t.Errorf("file removed by Update: status %d, want 404", code)
+	}
+}
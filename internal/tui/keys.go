@@ -0,0 +1,86 @@
+package tui
+
+import "io"
+
+// key is a decoded key press.
+type key int
+
+const (
+	keyNone key = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyEnter
+	keyBack // Backspace or Escape
+	keyQuit // Ctrl+C
+	keyRune // A printable character, returned alongside the key
+)
+
+// escapeSequences maps the terminal escape sequences we understand to keys.
+var escapeSequences = map[string]key{
+	"\x1b[A":  keyUp,
+	"\x1bOA":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1bOB":  keyDown,
+	"\x1b[C":  keyRight,
+	"\x1bOC":  keyRight,
+	"\x1b[D":  keyLeft,
+	"\x1bOD":  keyLeft,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+	"\x1b[H":  keyHome,
+	"\x1b[1~": keyHome,
+	"\x1bOH":  keyHome,
+	"\x1b[F":  keyEnd,
+	"\x1b[4~": keyEnd,
+	"\x1bOF":  keyEnd,
+}
+
+// readKey reads one key press from a terminal in raw mode. Unrecognized escape
+// sequences are reported as keyNone.
+func readKey(r io.Reader) (key, rune, error) {
+	var buf [16]byte
+	n, err := r.Read(buf[:])
+	if err != nil {
+		return keyNone, 0, err
+	}
+	in := string(buf[:n])
+
+	switch {
+	case in == "\x1b":
+		return keyBack, 0, nil
+	case in[0] == '\x1b':
+		return escapeSequences[in], 0, nil
+	case in == "\r" || in == "\n":
+		return keyEnter, 0, nil
+	case in == "\x7f" || in == "\b":
+		return keyBack, 0, nil
+	case in == "\x03":
+		return keyQuit, 0, nil
+	}
+
+	// Vim-style movement keys
+	switch r := []rune(in)[0]; r {
+	case 'k':
+		return keyUp, 0, nil
+	case 'j':
+		return keyDown, 0, nil
+	case 'h':
+		return keyLeft, 0, nil
+	case 'l':
+		return keyRight, 0, nil
+	case 'g':
+		return keyHome, 0, nil
+	case 'G':
+		return keyEnd, 0, nil
+	case ' ':
+		return keyPageDown, 0, nil
+	default:
+		return keyRune, r, nil
+	}
+}
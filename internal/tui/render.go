@@ -0,0 +1,292 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"ship-of-theseus/internal/visualizer"
+	"strings"
+)
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiReverse = "\x1b[7m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+)
+
+// render draws the current view as a full frame.
+func (e *explorer) render(w io.Writer) {
+	var rows []string
+	switch e.view {
+	case viewDirs:
+		rows = e.renderDirs()
+	case viewFile:
+		rows = e.renderFile()
+	case viewTimeline:
+		rows = e.renderTimeline()
+	case viewHelp:
+		rows = e.renderHelp()
+	}
+
+	// Home the cursor and overwrite in place, clearing leftovers, to avoid flicker
+	fmt.Fprint(w, "\x1b[H")
+	for i, row := range rows {
+		if i >= e.height {
+			break
+		}
+		fmt.Fprint(w, row, ansiReset, "\x1b[K")
+		if i < len(rows)-1 && i < e.height-1 {
+			fmt.Fprint(w, "\r\n")
+		}
+	}
+	fmt.Fprint(w, "\x1b[J")
+}
+
+// title is the top bar shared by every view.
+func (e *explorer) title() string {
+	a := e.analysis
+	pct := 0.0
+	if a.TotalLines > 0 {
+		pct = float64(a.OriginalLines) / float64(a.TotalLines) * 100.0
+	}
+	left := " Ship of Theseus explorer"
+	right := fmt.Sprintf("%.1f%% original · %d lines · %d files ", pct, a.TotalLines, len(a.FileAnalyses))
+	gap := max(e.width-len([]rune(left))-len([]rune(right)), 1)
+	return ansiReverse + fit(left+strings.Repeat(" ", gap)+right, e.width)
+}
+
+// footer pads the frame so the key help sits on the last row.
+func (e *explorer) footer(rows []string, keys string) []string {
+	for len(rows) < e.height-1 {
+		rows = append(rows, "")
+	}
+	return append(rows[:e.height-1], ansiDim+fit(" "+keys, e.width))
+}
+
+func (e *explorer) renderDirs() []string {
+	location := "/" + e.dir.path
+	order := "↑"
+	if e.reverse {
+		order = "↓"
+	}
+	sortLabel := fmt.Sprintf("sort: %s %s ", e.sortKey, order)
+
+	rows := []string{
+		e.title(),
+		ansiBold + fit(" "+location, e.width-len([]rune(sortLabel))) + sortLabel,
+	}
+
+	// Fixed columns: marker, files, lines, original % and bar, similarity
+	nameWidth := max(e.width-54, 10)
+	rows = append(rows, ansiDim+fit(fmt.Sprintf("   %-*s %6s %8s %9s %-10s %10s",
+		nameWidth, "Name", "Files", "Lines", "Original", "", "Similarity"), e.width))
+
+	height := e.bodyHeight()
+	e.offset = scroll(e.cursor, e.offset, height)
+	for i := e.offset; i < len(e.entries) && i < e.offset+height; i++ {
+		en := e.entries[i]
+		pct := en.originalPct()
+		row := fmt.Sprintf("   %s %6d %8d %8.1f%% %s %9.1f%%",
+			fit(en.name, nameWidth), en.files, en.totalLines, pct, bar(pct, 10), en.avgSimilarity*100)
+
+		style := visualizer.TierANSI(pct)
+		if i == e.cursor {
+			row = " ▶" + row[2:]
+			style += ansiReverse
+		}
+		rows = append(rows, style+fit(row, e.width))
+	}
+
+	if len(e.entries) == 0 {
+		rows = append(rows, "   (no analyzed files)")
+	}
+
+	return e.footer(rows, "↑↓ move  ⏎ open  ← up  s sort  r reverse  t timeline  ? help  q quit")
+}
+
+func (e *explorer) renderFile() []string {
+	f := e.file
+	pct := 0.0
+	if f.TotalLines > 0 {
+		pct = float64(f.OriginalLines) / float64(f.TotalLines) * 100.0
+	}
+
+	rows := []string{
+		e.title(),
+		ansiBold + fit(fmt.Sprintf(" %s  ·  %d code lines · %.1f%% original · %.1f%% avg similarity",
+			f.Path, f.TotalLines, pct, f.AvgSimilarity*100), e.width),
+		ansiDim + fit(fmt.Sprintf("   %5s %5s  %s", "Line", "Sim", "Code"), e.width),
+	}
+
+	height := e.bodyHeight()
+	switch {
+	case e.lineErr != nil:
+		rows = append(rows, fit("   Could not load line detail: "+e.lineErr.Error(), e.width))
+	case len(e.lines) == 0:
+		rows = append(rows, "   (no line-level detail)")
+	default:
+		e.lineOffset = scroll(e.lineCursor, e.lineOffset, height)
+		for i := e.lineOffset; i < len(e.lines) && i < e.lineOffset+height; i++ {
+			line := e.lines[i]
+			row := fmt.Sprintf("   %5d %4.0f%%  %s", line.CurrentLineNum, line.Similarity*100, line.CurrentLine)
+
+			style := visualizer.TierANSI(line.Similarity * 100)
+			if i == e.lineCursor {
+				row = " ▶" + row[2:]
+				style += ansiReverse
+			}
+			rows = append(rows, style+fit(row, e.width))
+		}
+	}
+
+	// Detail of the selected line sits just above the key help
+	for len(rows) < e.height-3 {
+		rows = append(rows, "")
+	}
+	rows = rows[:e.height-3]
+	if e.lineCursor < len(e.lines) {
+		line := e.lines[e.lineCursor]
+		origin := "new: no similar line in the first version"
+		if line.OriginalLine != "" {
+			origin = fmt.Sprintf("was L%d: %s", line.OriginalLineNum, line.OriginalLine)
+		}
+		rows = append(rows,
+			fit(" ↳ "+origin, e.width),
+			ansiDim+fit(fmt.Sprintf("   first %s · last %s (%s)",
+				short(line.FirstCommitHash), short(line.LastCommitHash),
+				line.LastCommitDate.Format("2006-01-02")), e.width))
+	}
+
+	return e.footer(rows, "↑↓ move  PgUp/PgDn page  ← back  t timeline  ? help  q quit")
+}
+
+func (e *explorer) renderTimeline() []string {
+	snapshots := e.analysis.HistoricalSnapshots
+	rows := []string{e.title(), ansiBold + fit(" Evolution timeline", e.width)}
+
+	if len(snapshots) == 0 {
+		rows = append(rows, "", "   No timeline available for this analysis.")
+		return e.footer(rows, "t back  q quit")
+	}
+
+	// Scale the y-axis to the data, keeping at least a 10-point range
+	lo, hi := 100.0, 0.0
+	for _, s := range snapshots {
+		lo, hi = min(lo, s.OriginalPct), max(hi, s.OriginalPct)
+	}
+	if hi-lo < 10 {
+		lo = min(max((hi+lo)/2-5, 0), 90)
+		hi = lo + 10
+	}
+
+	// Snapshots with sizes get a second status row
+	sized := snapshots[len(snapshots)-1].Lines > 0
+	chartHeight := max(e.height-6, 3)
+	if sized {
+		chartHeight = max(e.height-7, 3)
+	}
+	chartWidth := max(e.width-12, 10)
+
+	column := func(i int) int {
+		if len(snapshots) == 1 {
+			return 0
+		}
+		return i * (chartWidth - 1) / (len(snapshots) - 1)
+	}
+	level := func(pct float64) int {
+		return int((pct - lo) / (hi - lo) * float64(chartHeight-1))
+	}
+
+	grid := make([][]rune, chartHeight)
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(" ", chartWidth))
+	}
+	selX := column(e.snapshot)
+	for y := range grid {
+		grid[y][selX] = '┊'
+	}
+	for i, s := range snapshots {
+		y := chartHeight - 1 - level(s.OriginalPct)
+		mark := '●'
+		if i == e.snapshot {
+			mark = '◆'
+		}
+		grid[y][column(i)] = mark
+	}
+
+	for y, cells := range grid {
+		label := "        "
+		if y == 0 || y == chartHeight-1 || y == chartHeight/2 {
+			pct := hi - float64(y)/float64(chartHeight-1)*(hi-lo)
+			label = fmt.Sprintf("%6.1f%% ", pct)
+		}
+		rows = append(rows, fit(" "+label+"│"+string(cells), e.width))
+	}
+	rows = append(rows, fit(" "+strings.Repeat(" ", 8)+"└"+strings.Repeat("─", chartWidth), e.width))
+
+	first, last := snapshots[0].Date.Format("2006-01-02"), snapshots[len(snapshots)-1].Date.Format("2006-01-02")
+	rows = append(rows, fit(" "+strings.Repeat(" ", 9)+first+
+		strings.Repeat(" ", max(chartWidth-len(first)-len(last), 1))+last, e.width))
+
+	s := snapshots[e.snapshot]
+	kind := "estimated"
+	if e.snapshot == len(snapshots)-1 {
+		kind = "measured"
+	}
+	when := s.Date.Format("2006-01-02")
+	if s.Label != "" {
+		when += "  " + s.Label
+	}
+	rows = append(rows, visualizer.TierANSI(s.OriginalPct)+fit(fmt.Sprintf(" %d/%d  %s  %s  %.1f%% original (%s)",
+		e.snapshot+1, len(snapshots), short(s.CommitHash), when, s.OriginalPct, kind), e.width))
+	if sized {
+		stats := fmt.Sprintf("      %d lines in files  +%d -%d since the previous point  %d contributors",
+			s.Lines, s.LinesAdded, s.LinesRemoved, s.Contributors)
+		if s.TotalLines > 0 {
+			stats += fmt.Sprintf("  %d measured lines of code (%d original)", s.TotalLines, s.OriginalLines)
+		}
+		rows = append(rows, ansiDim+fit(stats, e.width))
+	}
+
+	return e.footer(rows, "←→ step  PgUp/PgDn jump  Home/End first/last  t back  ? help  q quit")
+}
+
+func (e *explorer) renderHelp() []string {
+	rows := []string{
+		e.title(),
+		"",
+		ansiBold + "   Directories",
+		"   ↑/↓ or k/j     Move the selection",
+		"   ⏎ or →         Open the selected directory or file",
+		"   ← or ⌫         Go up to the parent directory",
+		"   s              Cycle the sort column: originality, similarity, size, name",
+		"   o / a / z / n  Sort by originality / similarity / size / name",
+		"   r              Reverse the sort order",
+		"",
+		ansiBold + "   Files",
+		"   ↑/↓, PgUp/PgDn Move through the lines; the origin of the selected line is shown below",
+		"   ← or ⌫         Back to the directory",
+		"",
+		ansiBold + "   Timeline",
+		"   t              Show or hide the timeline",
+		"   ←/→            Step between snapshots; PgUp/PgDn jump 10; Home/End first/last",
+		"",
+		"   Rows are colored by originality tier, as in the interpretation guide.",
+		"   q or Ctrl+C quits. Press any key to return.",
+	}
+	return e.footer(rows, "any key to return")
+}
+
+// bar renders a percentage as a fixed-width bar.
+func bar(pct float64, width int) string {
+	filled := int(pct/100*float64(width) + 0.5)
+	filled = clamp(filled, 0, width)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+func short(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"path"
+	"ship-of-theseus/internal/models"
+	"sort"
+	"strings"
+)
+
+// dirNode is one directory of the rollup, with totals over everything below it.
+type dirNode struct {
+	name   string
+	path   string // Relative to the repository root; "" for the root
+	parent *dirNode
+	dirs   map[string]*dirNode
+	files  []*models.FileAnalysis
+
+	fileCount     int
+	totalLines    int
+	originalLines int
+	similaritySum float64 // Sum of per-file average similarity weighted by lines
+}
+
+// entry is a row of a directory listing: a subdirectory or a file.
+type entry struct {
+	name string
+	dir  *dirNode             // Set for subdirectories
+	file *models.FileAnalysis // Set for files
+
+	files         int
+	totalLines    int
+	originalLines int
+	avgSimilarity float64
+}
+
+func (e entry) originalPct() float64 {
+	if e.totalLines == 0 {
+		return 0
+	}
+	return float64(e.originalLines) / float64(e.totalLines) * 100.0
+}
+
+// sortKey selects the column a directory listing is ordered by.
+type sortKey int
+
+const (
+	byOriginality sortKey = iota // Least original first
+	bySimilarity                 // Lowest average similarity first
+	bySize                       // Most lines first
+	byName
+)
+
+var sortKeyNames = []string{"originality", "similarity", "size", "name"}
+
+func (k sortKey) String() string {
+	return sortKeyNames[k]
+}
+
+// buildRollup aggregates per-file results into a directory tree.
+func buildRollup(files []*models.FileAnalysis) *dirNode {
+	root := newDirNode("", "", nil)
+
+	for _, file := range files {
+		node := root
+		dir := path.Dir(file.Path)
+		if dir != "." {
+			for _, part := range strings.Split(dir, "/") {
+				child, ok := node.dirs[part]
+				if !ok {
+					child = newDirNode(part, path.Join(node.path, part), node)
+					node.dirs[part] = child
+				}
+				node = child
+			}
+		}
+		node.files = append(node.files, file)
+
+		// Every ancestor's totals include this file
+		for n := node; n != nil; n = n.parent {
+			n.fileCount++
+			n.totalLines += file.TotalLines
+			n.originalLines += file.OriginalLines
+			n.similaritySum += file.AvgSimilarity * float64(file.TotalLines)
+		}
+	}
+
+	return root
+}
+
+func newDirNode(name, dirPath string, parent *dirNode) *dirNode {
+	return &dirNode{name: name, path: dirPath, parent: parent, dirs: make(map[string]*dirNode)}
+}
+
+// entries lists a directory's subdirectories and files, sorted by key.
+func (d *dirNode) entries(key sortKey, reverse bool) []entry {
+	var list []entry
+	for _, sub := range d.dirs {
+		e := entry{
+			name:          sub.name + "/",
+			dir:           sub,
+			files:         sub.fileCount,
+			totalLines:    sub.totalLines,
+			originalLines: sub.originalLines,
+		}
+		if sub.totalLines > 0 {
+			e.avgSimilarity = sub.similaritySum / float64(sub.totalLines)
+		}
+		list = append(list, e)
+	}
+	for _, file := range d.files {
+		list = append(list, entry{
+			name:          path.Base(file.Path),
+			file:          file,
+			files:         1,
+			totalLines:    file.TotalLines,
+			originalLines: file.OriginalLines,
+			avgSimilarity: file.AvgSimilarity,
+		})
+	}
+
+	less := func(a, b entry) bool {
+		switch key {
+		case byOriginality:
+			if a.originalPct() != b.originalPct() {
+				return a.originalPct() < b.originalPct()
+			}
+		case bySimilarity:
+			if a.avgSimilarity != b.avgSimilarity {
+				return a.avgSimilarity < b.avgSimilarity
+			}
+		case bySize:
+			if a.totalLines != b.totalLines {
+				return a.totalLines > b.totalLines
+			}
+		}
+		return a.name < b.name
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if reverse {
+			return less(list[j], list[i])
+		}
+		return less(list[i], list[j])
+	})
+	return list
+}
@@ -0,0 +1,320 @@
+// Package tui implements the interactive terminal explorer: a keyboard-driven view of
+// an analysis with a directory rollup, per-file line coloring and the timeline.
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"ship-of-theseus/internal/models"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// LineLoader returns the line-level detail of a file. Analyses that spilled lines to
+// disk keep them out of FileAnalysis.LineHistories, so the explorer asks for them
+// only when a file is opened.
+type LineLoader func(file *models.FileAnalysis) ([]*models.LineHistory, error)
+
+// view identifies which screen the explorer is showing.
+type view int
+
+const (
+	viewDirs view = iota
+	viewFile
+	viewTimeline
+	viewHelp
+)
+
+// explorer holds the state of an interactive session.
+type explorer struct {
+	analysis  *models.CodebaseAnalysis
+	loadLines LineLoader
+
+	view     view
+	previous view // Where the help screen returns to
+	width    int
+	height   int
+
+	// Directory listing
+	dir     *dirNode
+	entries []entry
+	cursor  int
+	offset  int
+	sortKey sortKey
+	reverse bool
+
+	// File view
+	file       *models.FileAnalysis
+	lines      []*models.LineHistory
+	lineErr    error
+	lineCursor int
+	lineOffset int
+
+	// Timeline view
+	snapshot int
+}
+
+// CheckTerminal reports an error unless both stdin and stdout are terminals, which
+// the explorer needs. Callers can check before doing expensive work for it.
+func CheckTerminal() error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("the explorer needs an interactive terminal")
+	}
+	return nil
+}
+
+// Run starts the explorer on the terminal and returns when the user quits.
+// Both stdin and stdout must be terminals.
+func Run(analysis *models.CodebaseAnalysis, loadLines LineLoader) error {
+	if err := CheckTerminal(); err != nil {
+		return err
+	}
+	inFd, outFd := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return fmt.Errorf("failed to configure terminal: %w", err)
+	}
+	defer term.Restore(inFd, state)
+
+	// Alternate screen, hidden cursor; both undone on exit
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	e := &explorer{
+		analysis:  analysis,
+		loadLines: loadLines,
+		dir:       buildRollup(analysis.FileAnalyses),
+		snapshot:  len(analysis.HistoricalSnapshots) - 1,
+	}
+	e.refreshEntries()
+
+	out := bufio.NewWriter(os.Stdout)
+	for {
+		e.width, e.height, err = term.GetSize(outFd)
+		if err != nil || e.width < 40 || e.height < 10 {
+			e.width, e.height = max(e.width, 40), max(e.height, 10)
+		}
+
+		e.render(out)
+		if err := out.Flush(); err != nil {
+			return err
+		}
+
+		k, r, err := readKey(os.Stdin)
+		if err != nil {
+			return err
+		}
+		if !e.handleKey(k, r) {
+			return nil
+		}
+	}
+}
+
+// handleKey applies a key press and reports whether the explorer should keep running.
+func (e *explorer) handleKey(k key, r rune) bool {
+	if k == keyQuit || (k == keyRune && r == 'q') {
+		return false
+	}
+
+	if k == keyRune {
+		switch r {
+		case '?':
+			if e.view != viewHelp {
+				e.previous, e.view = e.view, viewHelp
+			}
+			return true
+		case 't':
+			if e.view == viewTimeline {
+				e.view = viewDirs
+			} else {
+				e.view = viewTimeline
+			}
+			return true
+		}
+	}
+
+	switch e.view {
+	case viewDirs:
+		e.handleDirKey(k, r)
+	case viewFile:
+		e.handleFileKey(k)
+	case viewTimeline:
+		e.handleTimelineKey(k)
+	case viewHelp:
+		e.view = e.previous
+	}
+	return true
+}
+
+func (e *explorer) handleDirKey(k key, r rune) {
+	switch k {
+	case keyUp:
+		e.cursor--
+	case keyDown:
+		e.cursor++
+	case keyPageUp:
+		e.cursor -= e.bodyHeight()
+	case keyPageDown:
+		e.cursor += e.bodyHeight()
+	case keyHome:
+		e.cursor = 0
+	case keyEnd:
+		e.cursor = len(e.entries) - 1
+	case keyEnter, keyRight:
+		if e.cursor < len(e.entries) {
+			e.open(e.entries[e.cursor])
+		}
+	case keyBack, keyLeft:
+		e.up()
+	case keyRune:
+		switch r {
+		case 's':
+			e.sortKey = (e.sortKey + 1) % sortKey(len(sortKeyNames))
+			e.refreshEntries()
+		case 'r':
+			e.reverse = !e.reverse
+			e.refreshEntries()
+		case 'o':
+			e.sortKey, e.reverse = byOriginality, false
+			e.refreshEntries()
+		case 'a':
+			e.sortKey, e.reverse = bySimilarity, false
+			e.refreshEntries()
+		case 'z':
+			e.sortKey, e.reverse = bySize, false
+			e.refreshEntries()
+		case 'n':
+			e.sortKey, e.reverse = byName, false
+			e.refreshEntries()
+		}
+	}
+	e.cursor = clamp(e.cursor, 0, len(e.entries)-1)
+}
+
+func (e *explorer) handleFileKey(k key) {
+	switch k {
+	case keyUp:
+		e.lineCursor--
+	case keyDown:
+		e.lineCursor++
+	case keyPageUp:
+		e.lineCursor -= e.bodyHeight()
+	case keyPageDown:
+		e.lineCursor += e.bodyHeight()
+	case keyHome:
+		e.lineCursor = 0
+	case keyEnd:
+		e.lineCursor = len(e.lines) - 1
+	case keyBack, keyLeft:
+		e.view = viewDirs
+		e.file, e.lines = nil, nil
+	}
+	e.lineCursor = clamp(e.lineCursor, 0, len(e.lines)-1)
+}
+
+func (e *explorer) handleTimelineKey(k key) {
+	switch k {
+	case keyLeft, keyUp:
+		e.snapshot--
+	case keyRight, keyDown:
+		e.snapshot++
+	case keyPageUp:
+		e.snapshot -= 10
+	case keyPageDown:
+		e.snapshot += 10
+	case keyHome:
+		e.snapshot = 0
+	case keyEnd:
+		e.snapshot = len(e.analysis.HistoricalSnapshots) - 1
+	case keyBack:
+		e.view = viewDirs
+	}
+	e.snapshot = clamp(e.snapshot, 0, len(e.analysis.HistoricalSnapshots)-1)
+}
+
+// open descends into a directory or opens a file.
+func (e *explorer) open(en entry) {
+	if en.dir != nil {
+		e.dir = en.dir
+		e.cursor, e.offset = 0, 0
+		e.refreshEntries()
+		return
+	}
+
+	e.file = en.file
+	e.lines, e.lineErr = en.file.LineHistories, nil
+	if e.lines == nil && e.loadLines != nil {
+		e.lines, e.lineErr = e.loadLines(en.file)
+	}
+	e.lineCursor, e.lineOffset = 0, 0
+	e.view = viewFile
+}
+
+// up returns to the parent directory, keeping the child selected.
+func (e *explorer) up() {
+	if e.dir.parent == nil {
+		return
+	}
+	child := e.dir
+	e.dir = child.parent
+	e.refreshEntries()
+
+	e.cursor, e.offset = 0, 0
+	for i, en := range e.entries {
+		if en.dir == child {
+			e.cursor = i
+		}
+	}
+}
+
+func (e *explorer) refreshEntries() {
+	e.entries = e.dir.entries(e.sortKey, e.reverse)
+}
+
+// bodyHeight is the number of rows available for list content.
+func (e *explorer) bodyHeight() int {
+	// Title, subtitle, column header and key help
+	reserved := 4
+	if e.view == viewFile {
+		reserved += 2 // Detail of the selected line
+	}
+	return max(e.height-reserved, 1)
+}
+
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
+
+// scroll adjusts offset so that cursor stays within a window of the given height.
+func scroll(cursor, offset, height int) int {
+	if cursor < offset {
+		return cursor
+	}
+	if cursor >= offset+height {
+		return cursor - height + 1
+	}
+	return offset
+}
+
+// fit truncates or pads s to exactly width columns. It counts runes, which is
+// accurate for the box-drawing characters and code the explorer displays.
+func fit(s string, width int) string {
+	s = strings.ReplaceAll(s, "\t", "    ")
+	runes := []rune(s)
+	if len(runes) > width {
+		if width <= 1 {
+			return string(runes[:max(width, 0)])
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
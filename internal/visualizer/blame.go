@@ -0,0 +1,68 @@
+package visualizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"ship-of-theseus/internal/models"
+	"strings"
+)
+
+// WriteBlame writes a git blame-style listing of a file: every line of content, with
+// analyzed lines prefixed by the last commit to modify them, the commit the file was
+// born in, their similarity to origin and the date of the last change. Blank lines
+// carry no annotation. With color, annotated lines are tinted by originality tier.
+func WriteBlame(w io.Writer, file *models.FileAnalysis, content string, color bool) error {
+	byLine := make(map[int]*models.LineHistory, len(file.LineHistories))
+	for _, line := range file.LineHistories {
+		byLine[line.CurrentLineNum] = line
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	numWidth := len(fmt.Sprint(len(lines)))
+
+	bw := bufio.NewWriter(w)
+	for i, text := range lines {
+		lineNum := i + 1
+		text = strings.TrimRight(text, "\r")
+
+		line, ok := byLine[lineNum]
+		if !ok {
+			fmt.Fprintf(bw, "%-7s %-7s (%4s %-10s %*d) %s\n", "", "", "", "", numWidth, lineNum, text)
+			continue
+		}
+
+		prefix := fmt.Sprintf("%-7s %-7s (%3.0f%% %s %*d)",
+			shortHash(line.LastCommitHash), shortHash(line.FirstCommitHash),
+			line.Similarity*100, line.LastCommitDate.Format("2006-01-02"), numWidth, lineNum)
+		fmt.Fprintf(bw, "%s %s\n", colorize(prefix, tierFor(line.Similarity*100), color), text)
+	}
+
+	return bw.Flush()
+}
+
+// WriteBlamePorcelain writes one machine-readable record per analyzed line, modeled on
+// git blame --line-porcelain so editor integrations can parse it the same way:
+//
+//	<last-commit> <birth-commit> <line> <original-line>
+//	similarity <0.0000-1.0000>
+//	original <true|false>
+//	last-commit-time <unix>
+//	original-line	<text of the matched line in the first version>
+//	\t<current line>
+//
+// Lines that were not analyzed (blank lines) are omitted. original-line is empty when
+// no similar line existed in the file's first version. isOriginal is the analysis's
+// verdict on a line's similarity, so the original field agrees with its totals.
+func WriteBlamePorcelain(w io.Writer, file *models.FileAnalysis, isOriginal func(similarity float64) bool) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range file.LineHistories {
+		fmt.Fprintf(bw, "%s %s %d %d\n", line.LastCommitHash, line.FirstCommitHash, line.CurrentLineNum, line.OriginalLineNum)
+		fmt.Fprintf(bw, "similarity %.4f\n", line.Similarity)
+		fmt.Fprintf(bw, "original %t\n", isOriginal(line.Similarity))
+		fmt.Fprintf(bw, "last-commit-time %d\n", line.LastCommitDate.Unix())
+		fmt.Fprintf(bw, "original-line\t%s\n", line.OriginalLine)
+		fmt.Fprintf(bw, "\t%s\n", line.CurrentLine)
+	}
+	return bw.Flush()
+}
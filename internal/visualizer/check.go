@@ -0,0 +1,45 @@
+package visualizer
+
+import (
+	"fmt"
+	"ship-of-theseus/internal/check"
+)
+
+// DisplayCheck prints the outcome of each quality gate condition, followed by a verdict.
+func DisplayCheck(results []check.Result) {
+	fmt.Println("🚦 ORIGINALITY CHECK")
+
+	for _, r := range results {
+		mark := "✅"
+		if !r.Passed {
+			mark = "❌"
+		}
+
+		var detail string
+		switch {
+		case r.Message != "":
+			detail = r.Message
+		case r.Kind == check.MaxDrop:
+			detail = fmt.Sprintf("dropped %.1f pts (%.1f%% → %.1f%%)", r.Actual, r.Baseline, r.Baseline-r.Actual)
+		default:
+			detail = fmt.Sprintf("actual %.1f%% across %d files", r.Actual, r.Files)
+		}
+
+		fmt.Printf("   %s %-30s %-22s %s\n", mark, truncatePath(r.Scope, 30), r.Condition, detail)
+	}
+	fmt.Println()
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+		}
+	}
+
+	if failed == 0 {
+		fmt.Printf("   All %d checks passed. The ship holds together.\n", len(results))
+	} else {
+		fmt.Printf("   %d of %d checks failed. Too many planks have been replaced.\n", failed, len(results))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,134 @@
+package visualizer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"ship-of-theseus/internal/report"
+)
+
+// DisplayCouplings prints the strongest couplings, at most limit of them (0 = all).
+func DisplayCouplings(c *report.Couplings, limit int) {
+	fmt.Println("🔗 CHANGE COUPLING")
+	fmt.Printf("   Files that change together, over %s commits", formatNumber(c.Commits))
+	if c.Skipped > 0 {
+		fmt.Printf(" (%s large commits skipped)", formatNumber(c.Skipped))
+	}
+	fmt.Println()
+	fmt.Println()
+
+	pairs := topCouplings(c, limit)
+	if len(pairs) == 0 {
+		fmt.Println("   No files changed together often enough in the selected history.")
+		fmt.Println()
+		return
+	}
+
+	for i, p := range pairs {
+		fmt.Println(strings.TrimRight(fmt.Sprintf("   %3d. %-50s %s", i+1, truncatePath(p.A.Path, 50), coupledOriginality(p.A)), " "))
+		fmt.Println(strings.TrimRight(fmt.Sprintf("        %-50s %s", truncatePath(p.B.Path, 50), coupledOriginality(p.B)), " "))
+		fmt.Printf("        %d shared commits · degree %.0f%% · %.0f%% of the first file's commits change the second, %.0f%% the other way\n",
+			p.Shared, p.Degree, p.A.Confidence, p.B.Confidence)
+	}
+	if len(pairs) < len(c.Pairs) {
+		fmt.Printf("   ... and %d more\n", len(c.Pairs)-len(pairs))
+	}
+	fmt.Println()
+
+	fmt.Println("   Degree: shared commits as a share of the average of both files' commits")
+	fmt.Println()
+}
+
+// WriteCouplingsMarkdown renders the strongest couplings as a Markdown table.
+func WriteCouplingsMarkdown(w io.Writer, c *report.Couplings, limit int) error {
+	var b strings.Builder
+
+	b.WriteString("# 🔗 Change Coupling\n\n")
+	fmt.Fprintf(&b, "Files that change together, over %s commits", formatNumber(c.Commits))
+	if c.Skipped > 0 {
+		fmt.Fprintf(&b, " (%s large commits skipped)", formatNumber(c.Skipped))
+	}
+	if c.CommitHash != "" {
+		fmt.Fprintf(&b, " · originality at `%s`", shortHash(c.CommitHash))
+	}
+	b.WriteString(".\n\n")
+
+	pairs := topCouplings(c, limit)
+	if len(pairs) == 0 {
+		b.WriteString("No files changed together often enough in the selected history.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	b.WriteString("| # | File | Coupled File | Shared Commits | Degree | Confidence | Original |\n")
+	b.WriteString("|---|---|---|---|---|---|---|\n")
+	for i, p := range pairs {
+		fmt.Fprintf(&b, "| %d | %s | %s | %d | %.0f%% | %.0f%% / %.0f%% | %s / %s |\n",
+			i+1, markdownCode(p.A.Path), markdownCode(p.B.Path), p.Shared, p.Degree, p.A.Confidence, p.B.Confidence,
+			coupledOriginalityCell(p.A), coupledOriginalityCell(p.B))
+	}
+	b.WriteString("\n")
+	b.WriteString("*Degree: shared commits as a share of the average of both files' commits. ")
+	b.WriteString("Confidence: how many of each file's commits also changed the other file.*\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteCouplingsCSV writes one row per coupled pair, for loading into spreadsheets.
+func WriteCouplingsCSV(w io.Writer, c *report.Couplings, limit int) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"file_a", "file_b", "shared_commits", "degree", "commits_a", "commits_b",
+		"confidence_a", "confidence_b", "original_pct_a", "original_pct_b"})
+	for _, p := range topCouplings(c, limit) {
+		cw.Write([]string{
+			p.A.Path,
+			p.B.Path,
+			fmt.Sprintf("%d", p.Shared),
+			fmt.Sprintf("%.2f", p.Degree),
+			fmt.Sprintf("%d", p.A.Commits),
+			fmt.Sprintf("%d", p.B.Commits),
+			fmt.Sprintf("%.2f", p.A.Confidence),
+			fmt.Sprintf("%.2f", p.B.Confidence),
+			csvOriginality(p.A),
+			csvOriginality(p.B),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// topCouplings returns the first limit pairs, or all of them when limit is 0.
+func topCouplings(c *report.Couplings, limit int) []report.Coupling {
+	if limit > 0 && limit < len(c.Pairs) {
+		return c.Pairs[:limit]
+	}
+	return c.Pairs
+}
+
+// coupledOriginality describes a coupled file's originality for the terminal, or
+// returns "" when it wasn't measured.
+func coupledOriginality(f report.CoupledFile) string {
+	if f.OriginalPct == nil {
+		return ""
+	}
+	return fmt.Sprintf("%5.1f%% original", *f.OriginalPct)
+}
+
+// coupledOriginalityCell is a coupled file's originality for a Markdown table.
+func coupledOriginalityCell(f report.CoupledFile) string {
+	if f.OriginalPct == nil {
+		return "—"
+	}
+	return fmt.Sprintf("%.1f%%", *f.OriginalPct)
+}
+
+// csvOriginality is a coupled file's originality for CSV, empty when not measured.
+func csvOriginality(f report.CoupledFile) string {
+	if f.OriginalPct == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", *f.OriginalPct)
+}
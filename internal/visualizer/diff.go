@@ -0,0 +1,63 @@
+package visualizer
+
+import (
+	"fmt"
+	"sort"
+
+	"ship-of-theseus/internal/report"
+)
+
+// DisplayDiff prints the overall change in originality between two reports, followed
+// by up to limit changed files, largest drop first.
+func DisplayDiff(d *report.Diff, limit int) {
+	fmt.Println("🔀 ORIGINALITY DIFF")
+	fmt.Printf("   Original code:  %.1f%% → %.1f%% (%+.1f pts)\n",
+		d.Base.OriginalPct, d.Head.OriginalPct, d.Head.OriginalPct-d.Base.OriginalPct)
+	fmt.Printf("   Total lines:    %s → %s (%+d)\n",
+		formatNumber(d.Base.TotalLines), formatNumber(d.Head.TotalLines), d.Head.TotalLines-d.Base.TotalLines)
+	fmt.Printf("   Original lines: %s → %s (%+d)\n",
+		formatNumber(d.Base.OriginalLines), formatNumber(d.Head.OriginalLines), d.Head.OriginalLines-d.Base.OriginalLines)
+	fmt.Println()
+
+	if len(d.Base.Files) == 0 || len(d.Head.Files) == 0 {
+		fmt.Println("   No per-file detail in one of the reports.")
+		fmt.Println()
+		return
+	}
+
+	if len(d.Files) == 0 {
+		fmt.Println("   No files changed.")
+		fmt.Println()
+		return
+	}
+
+	files := make([]report.FileDelta, len(d.Files))
+	copy(files, d.Files)
+	sort.SliceStable(files, func(i, j int) bool {
+		bi, hi := files[i].OriginalPct()
+		bj, hj := files[j].OriginalPct()
+		return hi-bi < hj-bj
+	})
+
+	fmt.Printf("📂 CHANGED FILES (%d)\n", len(files))
+	for i, f := range files {
+		if limit > 0 && i >= limit {
+			fmt.Printf("   ... and %d more\n", len(files)-limit)
+			break
+		}
+
+		base, head := f.OriginalPct()
+		switch {
+		case f.Base == nil:
+			fmt.Printf("   %-50s %6s → %5.1f%%   added (%s lines)\n",
+				truncatePath(f.Path, 50), "", head, formatNumber(f.Head.TotalLines))
+		case f.Head == nil:
+			fmt.Printf("   %-50s %5.1f%% → %6s   removed (%s lines)\n",
+				truncatePath(f.Path, 50), base, "", formatNumber(f.Base.TotalLines))
+		default:
+			fmt.Printf("   %-50s %5.1f%% → %5.1f%%  %+6.1f pts (%+d lines)\n",
+				truncatePath(f.Path, 50), base, head, head-base, f.Head.TotalLines-f.Base.TotalLines)
+		}
+	}
+	fmt.Println()
+}
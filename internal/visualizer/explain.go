@@ -0,0 +1,74 @@
+package visualizer
+
+import (
+	"fmt"
+	"ship-of-theseus/internal/models"
+)
+
+// DisplayExplanation walks through how a single line was classified, step by step.
+func DisplayExplanation(e *models.LineExplanation) {
+	r := e.Result
+
+	fmt.Println()
+	fmt.Printf("🔬 EXPLAIN %s:%d\n", e.FilePath, e.LineNum)
+	fmt.Printf("   Current line:   %s\n", formatCode(r.CurrentLine))
+	fmt.Printf("   Last modified:  %s (%s)\n", shortHash(r.LastCommitHash), r.LastCommitDate.Format("2006-01-02"))
+	fmt.Println()
+
+	fmt.Println("   1. Origin")
+	if e.OriginName != "" {
+		fmt.Printf("      Every file is compared with its version at %s.\n", e.OriginName)
+	} else if e.FirstCommit != "" {
+		fmt.Printf("      git log --follow lists %d commit(s) for this file; the oldest, %s, is the origin.\n",
+			len(e.FileCommits), shortHash(e.FirstCommit))
+	}
+	if e.FallbackReason != "" {
+		fmt.Printf("      %s.\n", e.FallbackReason)
+	}
+	fmt.Println()
+
+	if e.FallbackReason == "" {
+		fmt.Println("   2. Search window")
+		if len(e.Candidates) == 0 {
+			fmt.Printf("      The original version has no lines within ±%d of line %d.\n", e.WindowRadius, e.LineNum)
+		} else {
+			fmt.Printf("      Lines %d–%d of the original version (line %d ± %d), scored against the current line.\n",
+				e.WindowStart, e.WindowEnd, e.LineNum, e.WindowRadius)
+			fmt.Printf("      Candidates need at least %.0f%% similarity; the best one is the match.\n", e.Threshold*100)
+			fmt.Println()
+
+			for _, c := range e.Candidates {
+				marker := " "
+				note := ""
+				switch {
+				case r.OriginalLine != "" && c.LineNum == r.OriginalLineNum:
+					marker = "▶"
+					note = "  ← match"
+				case c.Similarity < e.Threshold:
+					note = "  (below threshold)"
+				}
+				fmt.Printf("      %s L%-5d %5.1f%%  %s%s\n", marker, c.LineNum, c.Similarity*100,
+					formatCode(c.Line), note)
+			}
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("   3. Verdict")
+	switch {
+	case e.FallbackReason != "":
+		fmt.Printf("      No comparison was possible; similarity is taken as %.0f%%.\n", r.Similarity*100)
+	case r.OriginalLine == "":
+		fmt.Println("      No candidate reached the threshold, so the line is compared with nothing:")
+		fmt.Printf("      similarity %.1f%%.\n", r.Similarity*100)
+	default:
+		fmt.Printf("      Similarity to L%d of %s: %.1f%%.\n", r.OriginalLineNum, shortHash(r.FirstCommitHash), r.Similarity*100)
+	}
+
+	if e.Original {
+		fmt.Printf("      ✅ ORIGINAL: %.1f%% ≥ %.0f%% threshold\n", r.Similarity*100, e.Threshold*100)
+	} else {
+		fmt.Printf("      ❌ NOT ORIGINAL: %.1f%% < %.0f%% threshold\n", r.Similarity*100, e.Threshold*100)
+	}
+	fmt.Println()
+}
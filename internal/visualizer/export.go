@@ -0,0 +1,120 @@
+package visualizer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"ship-of-theseus/internal/models"
+)
+
+// FileWriter streams per-file results to an output as they are produced.
+// Exporters implement it so that results can be written while the analysis runs
+// rather than after every file has been held in memory.
+type FileWriter interface {
+	WriteFile(file *models.FileAnalysis) error
+	Flush() error
+}
+
+// CSVWriter writes one row per analyzed file, for loading into spreadsheets or DuckDB.
+type CSVWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVWriter creates a CSVWriter. The header row is written with the first file.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// csvHeader lists the CSV columns in output order.
+var csvHeader = []string{"path", "total_lines", "original_lines", "original_pct", "avg_similarity"}
+
+// WriteFile appends a row for the given file.
+func (c *CSVWriter) WriteFile(file *models.FileAnalysis) error {
+	if !c.wroteHeader {
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	return c.w.Write([]string{
+		file.Path,
+		fmt.Sprintf("%d", file.TotalLines),
+		fmt.Sprintf("%d", file.OriginalLines),
+		fmt.Sprintf("%.2f", fileOriginalPct(file)),
+		fmt.Sprintf("%.4f", file.AvgSimilarity),
+	})
+}
+
+// Flush writes any buffered rows. A header is emitted even when no files were written.
+func (c *CSVWriter) Flush() error {
+	if !c.wroteHeader {
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// NDJSONWriter writes newline-delimited JSON. By default each object summarizes a
+// file; in line mode each object is a single LineHistory tagged with its path.
+type NDJSONWriter struct {
+	w     *bufio.Writer
+	enc   *json.Encoder
+	lines bool
+}
+
+// fileRecord is the NDJSON shape of a per-file summary.
+type fileRecord struct {
+	Path          string  `json:"path"`
+	TotalLines    int     `json:"total_lines"`
+	OriginalLines int     `json:"original_lines"`
+	OriginalPct   float64 `json:"original_pct"`
+	AvgSimilarity float64 `json:"avg_similarity"`
+}
+
+// lineRecord is the NDJSON shape of a single traced line.
+type lineRecord struct {
+	Path string `json:"path"`
+	*models.LineHistory
+}
+
+// NewNDJSONWriter creates an NDJSONWriter. When lines is true, one object is written
+// per LineHistory instead of one per file.
+func NewNDJSONWriter(w io.Writer, lines bool) *NDJSONWriter {
+	bw := bufio.NewWriter(w)
+	return &NDJSONWriter{w: bw, enc: json.NewEncoder(bw), lines: lines}
+}
+
+// WriteFile writes the objects for one file.
+func (n *NDJSONWriter) WriteFile(file *models.FileAnalysis) error {
+	if !n.lines {
+		return n.enc.Encode(fileRecord{
+			Path:          file.Path,
+			TotalLines:    file.TotalLines,
+			OriginalLines: file.OriginalLines,
+			OriginalPct:   fileOriginalPct(file),
+			AvgSimilarity: file.AvgSimilarity,
+		})
+	}
+
+	for _, line := range file.LineHistories {
+		if err := n.enc.Encode(lineRecord{Path: file.Path, LineHistory: line}); err != nil {
+			return err
+		}
+	}
+
+	// Flush per file so consumers tailing the stream see results as files complete
+	return n.w.Flush()
+}
+
+// Flush writes any buffered output.
+func (n *NDJSONWriter) Flush() error {
+	return n.w.Flush()
+}
@@ -0,0 +1,73 @@
+package visualizer
+
+import (
+	"fmt"
+	"ship-of-theseus/internal/models"
+	"strings"
+)
+
+// ansiReset ends a colored span started with a tier's ANSI code.
+const ansiReset = "\033[0m"
+
+// maxCodeWidth bounds how much of each source line is printed.
+const maxCodeWidth = 100
+
+// DisplayFile prints a single file's analysis line by line: each code line with its
+// similarity to origin, the line it was matched to in the file's first version, and
+// the commits that introduced and last touched it. With color, lines are tinted by
+// the same tiers used for the whole codebase.
+func DisplayFile(file *models.FileAnalysis, color bool) {
+	originalPct := fileOriginalPct(file)
+
+	fmt.Println()
+	fmt.Printf("🔎 %s\n", file.Path)
+	fmt.Printf("   Code lines:      %s\n", formatNumber(file.TotalLines))
+	fmt.Printf("   Original lines:  %s (%.1f%%)\n", formatNumber(file.OriginalLines), originalPct)
+	fmt.Printf("   Avg similarity:  %.1f%%\n", file.AvgSimilarity*100)
+	fmt.Println()
+
+	fmt.Printf("   %5s  %4s  %-7s  %-7s  %s\n", "Line", "Sim", "First", "Last", "Code")
+	for _, line := range file.LineHistories {
+		t := tierFor(line.Similarity * 100)
+
+		row := fmt.Sprintf("   %5d  %3.0f%%  %-7s  %-7s  %s",
+			line.CurrentLineNum, line.Similarity*100,
+			shortHash(line.FirstCommitHash), shortHash(line.LastCommitHash),
+			formatCode(line.CurrentLine))
+		fmt.Println(colorize(row, t, color))
+
+		// Show what the line started as whenever it has changed
+		if line.Similarity < 1.0 {
+			var origin string
+			if line.OriginalLine == "" {
+				origin = "new: no similar line in the first version"
+			} else {
+				origin = fmt.Sprintf("was L%d: %s", line.OriginalLineNum, formatCode(line.OriginalLine))
+			}
+			fmt.Printf("%34s↳ %s\n", "", origin)
+		}
+	}
+	fmt.Println()
+
+	t := tierFor(originalPct)
+	fmt.Printf("   %s %.1f%% of this file's code lines are still recognizably original.\n", t.Emoji, originalPct)
+	fmt.Println()
+}
+
+// colorize wraps s in the tier's terminal color when color is enabled.
+func colorize(s string, t tier, color bool) string {
+	if !color {
+		return s
+	}
+	return t.ANSI + s + ansiReset
+}
+
+// formatCode prepares a source line for single-line display: tabs are expanded and
+// long lines are truncated.
+func formatCode(line string) string {
+	line = strings.ReplaceAll(strings.TrimRight(line, "\r"), "\t", "    ")
+	if runes := []rune(line); len(runes) > maxCodeWidth {
+		return string(runes[:maxCodeWidth-3]) + "..."
+	}
+	return line
+}
@@ -0,0 +1,153 @@
+package visualizer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"ship-of-theseus/internal/report"
+)
+
+// fleetFile is a file of a fleet, qualified by its repository.
+type fleetFile struct {
+	Repo string
+	report.FileSummary
+}
+
+// reposByOriginality returns the repositories of a fleet, least original first.
+func reposByOriginality(f *report.Fleet) []report.FleetRepo {
+	repos := make([]report.FleetRepo, len(f.Repos))
+	copy(repos, f.Repos)
+	sort.SliceStable(repos, func(i, j int) bool {
+		return repos[i].Report.OriginalPct < repos[j].Report.OriginalPct
+	})
+	return repos
+}
+
+// mostTransformedFiles returns up to limit files across the fleet, least original first.
+func mostTransformedFiles(f *report.Fleet, limit int) []fleetFile {
+	var files []fleetFile
+	for _, repo := range f.Repos {
+		for _, file := range repo.Report.Files {
+			if file.TotalLines > 0 {
+				files = append(files, fleetFile{Repo: repo.Name, FileSummary: file})
+			}
+		}
+	}
+
+	pct := func(file fleetFile) float64 {
+		return float64(file.OriginalLines) / float64(file.TotalLines)
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return pct(files[i]) < pct(files[j])
+	})
+
+	return files[:min(limit, len(files))]
+}
+
+// DisplayFleet prints the combined report of several repositories: fleet-wide totals,
+// then each repository (least original first) and the most transformed files overall.
+func DisplayFleet(f *report.Fleet) {
+	printHeader()
+
+	fmt.Println("📊 FLEET STATISTICS")
+	fmt.Printf("   Repositories:           %d\n", len(f.Repos))
+	fmt.Printf("   Total Lines of Code:    %s\n", formatNumber(f.TotalLines))
+	fmt.Printf("   Original Lines:         %s (%.1f%%)\n", formatNumber(f.OriginalLines), f.OriginalPct)
+	fmt.Printf("   Average Similarity:     %.1f%%\n", f.AverageSimilarity*100)
+	fmt.Println()
+
+	fmt.Println("💭 INTERPRETATION")
+	t := tierFor(f.OriginalPct)
+	fmt.Printf("   %s %s\n", t.Emoji, t.Message)
+	fmt.Println()
+
+	fmt.Println("🚢 REPOSITORIES")
+	fmt.Printf("   %-28s %9s %10s %10s  %s\n", "Name", "Original", "Similarity", "Lines", "Commit")
+	for _, repo := range reposByOriginality(f) {
+		r := repo.Report
+		fmt.Printf("   %-28s %8.1f%% %9.1f%% %10s  %s\n",
+			truncatePath(repo.Name, 28), r.OriginalPct, r.AverageSimilarity*100,
+			formatNumber(r.TotalLines), shortHash(r.CommitHash))
+	}
+	fmt.Println()
+
+	fmt.Println("🔥 TOP 10 MOST TRANSFORMED FILES")
+	for i, file := range mostTransformedFiles(f, 10) {
+		path := file.Repo + ": " + file.Path
+		pct := float64(file.OriginalLines) / float64(file.TotalLines) * 100.0
+		fmt.Printf("   %2d. %-50s %5.1f%% original\n", i+1, truncatePath(path, 50), pct)
+	}
+	fmt.Println()
+}
+
+// WriteFleetMarkdown renders the combined report of several repositories as Markdown.
+func WriteFleetMarkdown(w io.Writer, f *report.Fleet) error {
+	var b strings.Builder
+
+	b.WriteString("# 🚢 Ship of Theseus Fleet Report\n\n")
+	fmt.Fprintf(&b, "**%.1f%% original code remaining** across %d repositories\n\n", f.OriginalPct, len(f.Repos))
+
+	t := tierFor(f.OriginalPct)
+	fmt.Fprintf(&b, "> %s %s\n\n", t.Emoji, strings.ReplaceAll(t.Message, "\n   ", " "))
+
+	b.WriteString("## Fleet Statistics\n\n")
+	b.WriteString("| Metric | Value |\n")
+	b.WriteString("|---|---|\n")
+	fmt.Fprintf(&b, "| Repositories | %d |\n", len(f.Repos))
+	fmt.Fprintf(&b, "| Total lines of code | %s |\n", formatNumber(f.TotalLines))
+	fmt.Fprintf(&b, "| Original lines | %s (%.1f%%) |\n", formatNumber(f.OriginalLines), f.OriginalPct)
+	fmt.Fprintf(&b, "| Average similarity | %.1f%% |\n", f.AverageSimilarity*100)
+	b.WriteString("\n")
+
+	b.WriteString("## Repositories\n\n")
+	b.WriteString("| Repository | Original | Avg. Similarity | Lines of Code | Commit |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, repo := range reposByOriginality(f) {
+		r := repo.Report
+		commitCell := "—"
+		if r.CommitHash != "" {
+			commitCell = "`" + shortHash(r.CommitHash) + "`"
+		}
+		fmt.Fprintf(&b, "| %s | %.1f%% | %.1f%% | %s | %s |\n",
+			strings.ReplaceAll(repo.Name, "|", `\|`), r.OriginalPct, r.AverageSimilarity*100, formatNumber(r.TotalLines), commitCell)
+	}
+	b.WriteString("\n")
+
+	if files := mostTransformedFiles(f, 10); len(files) > 0 {
+		b.WriteString("## Most Transformed Files\n\n")
+		b.WriteString("| # | Repository | File | Original | Lines |\n")
+		b.WriteString("|---|---|---|---|---|\n")
+		for i, file := range files {
+			pct := float64(file.OriginalLines) / float64(file.TotalLines) * 100.0
+			fmt.Fprintf(&b, "| %d | %s | %s | %.1f%% | %s |\n",
+				i+1, strings.ReplaceAll(file.Repo, "|", `\|`), markdownCode(file.Path), pct, formatNumber(file.TotalLines))
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteFleetCSV writes one row per repository, for loading into spreadsheets.
+func WriteFleetCSV(w io.Writer, f *report.Fleet) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"repo", "path", "commit", "total_lines", "original_lines", "original_pct", "avg_similarity"})
+	for _, repo := range f.Repos {
+		r := repo.Report
+		cw.Write([]string{
+			repo.Name,
+			repo.Path,
+			r.CommitHash,
+			fmt.Sprintf("%d", r.TotalLines),
+			fmt.Sprintf("%d", r.OriginalLines),
+			fmt.Sprintf("%.2f", r.OriginalPct),
+			fmt.Sprintf("%.4f", r.AverageSimilarity),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"ship-of-theseus/internal/models"
 	"sort"
-	"strings"
 )
 
 // Display prints the complete analysis results to the terminal with beautiful formatting.
@@ -47,9 +46,47 @@ func printOverallStats(analysis *models.CodebaseAnalysis) {
 	fmt.Printf("   Original Lines:         %s (%.1f%%)\n",
 		formatNumber(analysis.OriginalLines), originalPct)
 	fmt.Printf("   Average Similarity:     %.1f%%\n", analysis.AverageSimilarity*100)
+	if analysis.Origin != "" {
+		fmt.Printf("   Compared With:          %s\n", analysis.Origin)
+	}
 	fmt.Println()
 }
 
+// tier is one band of the interpretation scale. The same bands drive the terminal
+// commentary and the colors used by the SVG exporters.
+type tier struct {
+	MinPct  float64 // Lower bound (inclusive) of this band
+	Emoji   string  // Emoji shown in the terminal interpretation
+	Message string  // Philosophical commentary for this band
+	Color   string  // Badge/chart color, matching the shields.io palette
+	ANSI    string  // Terminal color for line-level output, closest to Color
+}
+
+// tiers lists the interpretation bands from most to least original.
+var tiers = []tier{
+	{80, "🏛️", "This codebase is remarkably stable, like a well-preserved ancient monument.\n   Most code remains close to its original form.", "#4c1", "\033[92m"},
+	{60, "⚓", "This codebase maintains strong continuity with its origins.\n   Core structures persist through evolution.", "#97ca00", "\033[32m"},
+	{40, "🌊", "This codebase has undergone substantial evolution.\n   Like Theseus's ship, many planks have been replaced.", "#dfb317", "\033[33m"},
+	{20, "⚡", "This codebase has been heavily transformed.\n   Few traces of the original code remain.", "#fe7d37", "\033[38;5;208m"},
+	{0, "🔥", "This codebase has been completely reimagined.\n   It bears little resemblance to its origins.", "#e05d44", "\033[31m"},
+}
+
+// tierFor returns the interpretation band for an originality percentage.
+func tierFor(originalPct float64) tier {
+	for _, t := range tiers {
+		if originalPct >= t.MinPct {
+			return t
+		}
+	}
+	return tiers[len(tiers)-1]
+}
+
+// TierANSI returns the terminal color escape for an originality (or similarity)
+// percentage, so other terminal front ends color by the same tiers.
+func TierANSI(pct float64) string {
+	return tierFor(pct).ANSI
+}
+
 // printInterpretation provides philosophical context based on the originality percentage.
 func printInterpretation(analysis *models.CodebaseAnalysis) {
 	originalPct := 0.0
@@ -59,26 +96,8 @@ func printInterpretation(analysis *models.CodebaseAnalysis) {
 
 	fmt.Println("💭 INTERPRETATION")
 
-	var emoji, message string
-	switch {
-	case originalPct >= 80:
-		emoji = "🏛️"
-		message = "This codebase is remarkably stable, like a well-preserved ancient monument.\n   Most code remains close to its original form."
-	case originalPct >= 60:
-		emoji = "⚓"
-		message = "This codebase maintains strong continuity with its origins.\n   Core structures persist through evolution."
-	case originalPct >= 40:
-		emoji = "🌊"
-		message = "This codebase has undergone substantial evolution.\n   Like Theseus's ship, many planks have been replaced."
-	case originalPct >= 20:
-		emoji = "⚡"
-		message = "This codebase has been heavily transformed.\n   Few traces of the original code remain."
-	default:
-		emoji = "🔥"
-		message = "This codebase has been completely reimagined.\n   It bears little resemblance to its origins."
-	}
-
-	fmt.Printf("   %s %s\n", emoji, message)
+	t := tierFor(originalPct)
+	fmt.Printf("   %s %s\n", t.Emoji, t.Message)
 	fmt.Println()
 }
 
@@ -109,102 +128,21 @@ func printProgressBar(analysis *models.CodebaseAnalysis) {
 	fmt.Println()
 }
 
-// printTimeline displays an ASCII graph of code evolution over time.
-func printTimeline(snapshots []models.Snapshot) {
+// DisplayTimeline prints only the evolution timeline, for callers that skip the
+// rest of the report.
+func DisplayTimeline(snapshots []models.Snapshot) {
 	if len(snapshots) < 2 {
-		return
-	}
-
-	fmt.Println("📉 EVOLUTION TIMELINE")
-	fmt.Println()
-
-	// Graph parameters
-	height := 10
-	width := 60
-
-	// Find min/max for scaling
-	minPct, maxPct := 100.0, 0.0
-	for _, s := range snapshots {
-		if s.OriginalPct < minPct {
-			minPct = s.OriginalPct
-		}
-		if s.OriginalPct > maxPct {
-			maxPct = s.OriginalPct
-		}
-	}
-
-	// Add some padding
-	pctRange := maxPct - minPct
-	if pctRange < 10 {
-		pctRange = 10
-	}
-	minPct -= pctRange * 0.1
-	maxPct += pctRange * 0.1
-	if minPct < 0 {
-		minPct = 0
-	}
-	if maxPct > 100 {
-		maxPct = 100
-	}
-
-	// Draw graph from top to bottom
-	for y := height; y >= 0; y-- {
-		// Calculate the percentage this row represents
-		rowPct := minPct + (maxPct-minPct)*float64(y)/float64(height)
-
-		// Y-axis label
-		fmt.Printf("   %5.0f%% │", rowPct)
-
-		// Plot points
-		for x := 0; x < width; x++ {
-			// Map x position to snapshot index
-			idx := int(float64(x) / float64(width) * float64(len(snapshots)-1))
-			snapPct := snapshots[idx].OriginalPct
-
-			// Check if this snapshot's value is close to this row
-			if snapPct >= rowPct-((maxPct-minPct)/float64(height)/2) &&
-				snapPct <= rowPct+((maxPct-minPct)/float64(height)/2) {
-				fmt.Print("●")
-			} else if snapPct > rowPct {
-				// Value is above this row
-				fmt.Print(" ")
-			} else {
-				// Value is below this row
-				fmt.Print(" ")
-			}
-		}
+		fmt.Println("📉 EVOLUTION TIMELINE")
+		fmt.Printf("   %d snapshot(s); at least 2 commits are needed to plot a timeline.\n", len(snapshots))
 		fmt.Println()
+		return
 	}
 
-	// X-axis
-	fmt.Print("          └")
-	fmt.Print(strings.Repeat("─", width))
-	fmt.Println("▶")
-
-	// Smart date formatting based on time span
-	timeSpan := snapshots[len(snapshots)-1].Date.Sub(snapshots[0].Date)
-	var dateFormat string
-
-	switch {
-	case timeSpan.Hours() < 24*365: // Less than 1 year - show month and day
-		dateFormat = "Jan 02"
-	case timeSpan.Hours() < 24*365*3: // 1-3 years - show year-month
-		dateFormat = "2006-01"
-	default: // More than 3 years - show just year
-		dateFormat = "2006"
-	}
-
-	leftLabel := snapshots[0].Date.Format(dateFormat)
-	rightLabel := snapshots[len(snapshots)-1].Date.Format(dateFormat)
-	padding := width - len(leftLabel) - len(rightLabel)
-	if padding < 0 {
-		padding = 0
-	}
+	printTimeline(snapshots)
 
-	fmt.Printf("           %s%s%s\n",
-		leftLabel,
-		strings.Repeat(" ", padding),
-		rightLabel)
+	last := snapshots[len(snapshots)-1]
+	fmt.Printf("   %d sampled commits; the latest (%.1f%% original) is measured, earlier points are estimated.\n",
+		len(snapshots), last.OriginalPct)
 	fmt.Println()
 }
 
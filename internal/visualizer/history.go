@@ -0,0 +1,49 @@
+package visualizer
+
+import (
+	"fmt"
+	"ship-of-theseus/internal/report"
+)
+
+// DisplayHistory prints stored analysis runs as a table, oldest first.
+func DisplayHistory(reports []*report.Report) {
+	fmt.Println("📜 ANALYSIS HISTORY")
+
+	if len(reports) == 0 {
+		fmt.Println("   No recorded runs yet. Record one with: ship-of-theseus analyze --record")
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("   %-10s  %-8s  %9s  %10s  %12s  %s\n", "Date", "Commit", "Original", "Similarity", "Lines", "Version")
+	for _, r := range reports {
+		date := r.GeneratedAt
+		if r.CommitDate != nil {
+			date = *r.CommitDate
+		}
+		fmt.Printf("   %-10s  %-8s  %8.1f%%  %9.1f%%  %12s  %s\n",
+			date.Format("2006-01-02"), shortHash(r.CommitHash), r.OriginalPct,
+			r.AverageSimilarity*100, formatNumber(r.TotalLines), r.ToolVersion)
+	}
+	fmt.Println()
+}
+
+// DisplayTrend plots the measured originality of stored runs. Unlike the estimated
+// timeline of a single analysis, every point here is a real measurement.
+func DisplayTrend(reports []*report.Report) {
+	if len(reports) < 2 {
+		fmt.Println("📉 MEASURED TREND")
+		fmt.Printf("   %d recorded run(s); at least 2 are needed to plot a trend.\n", len(reports))
+		fmt.Println()
+		return
+	}
+
+	printTimeline(report.ToSnapshots(reports))
+
+	first, last := reports[0], reports[len(reports)-1]
+	delta := last.OriginalPct - first.OriginalPct
+	fmt.Printf("   %d measured runs: %.1f%% → %.1f%% original (%+.1f pts), %s → %s lines\n",
+		len(reports), first.OriginalPct, last.OriginalPct, delta,
+		formatNumber(first.TotalLines), formatNumber(last.TotalLines))
+	fmt.Println()
+}
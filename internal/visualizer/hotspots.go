@@ -0,0 +1,92 @@
+package visualizer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"ship-of-theseus/internal/report"
+)
+
+// DisplayHotspots prints the highest-scoring hotspots, at most limit of them (0 = all).
+func DisplayHotspots(h *report.Hotspots, limit int) {
+	fmt.Println("🔥 CHURN HOTSPOTS")
+	fmt.Printf("   Files that change often and have been largely rewritten, over %s commits\n", formatNumber(h.Commits))
+	fmt.Println()
+
+	files := topHotspots(h, limit)
+	if len(files) == 0 {
+		fmt.Println("   No analyzed file has changed in the selected history.")
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("   %3s  %-44s %7s %8s %15s %9s %6s\n", "#", "File", "Commits", "Lines", "Changed", "Original", "Score")
+	for i, f := range files {
+		fmt.Printf("   %3d. %-44s %7d %8s %15s %8.1f%% %6.1f\n",
+			i+1, truncatePath(f.Path, 44), f.Commits, formatNumber(f.TotalLines),
+			fmt.Sprintf("+%s -%s", formatNumber(f.LinesAdded), formatNumber(f.LinesRemoved)),
+			f.OriginalPct, f.Score)
+	}
+	if len(files) < len(h.Files) {
+		fmt.Printf("   ... and %d more\n", len(h.Files)-len(files))
+	}
+	fmt.Println()
+
+	fmt.Println("   Score: churn relative to the most churned file × share rewritten (0-100)")
+	fmt.Println()
+}
+
+// WriteHotspotsMarkdown renders the highest-scoring hotspots as a Markdown table.
+func WriteHotspotsMarkdown(w io.Writer, h *report.Hotspots, limit int) error {
+	var b strings.Builder
+
+	b.WriteString("# 🔥 Churn Hotspots\n\n")
+	fmt.Fprintf(&b, "Files that change often and have been largely rewritten, over %s commits", formatNumber(h.Commits))
+	if h.CommitHash != "" {
+		fmt.Fprintf(&b, " · originality at `%s`", shortHash(h.CommitHash))
+	}
+	b.WriteString(".\n\n")
+
+	b.WriteString("| # | File | Commits | Lines | Added | Removed | Original | Score |\n")
+	b.WriteString("|---|---|---|---|---|---|---|---|\n")
+	for i, f := range topHotspots(h, limit) {
+		fmt.Fprintf(&b, "| %d | %s | %d | %s | +%s | -%s | %.1f%% | %.1f |\n",
+			i+1, markdownCode(f.Path), f.Commits, formatNumber(f.TotalLines),
+			formatNumber(f.LinesAdded), formatNumber(f.LinesRemoved), f.OriginalPct, f.Score)
+	}
+	b.WriteString("\n")
+	b.WriteString("*Score: churn relative to the most churned file (commits and lines changed) × share rewritten, 0-100.*\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteHotspotsCSV writes one row per hotspot, for loading into spreadsheets.
+func WriteHotspotsCSV(w io.Writer, h *report.Hotspots, limit int) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"path", "commits", "lines_added", "lines_removed", "total_lines", "original_lines", "original_pct", "score"})
+	for _, f := range topHotspots(h, limit) {
+		cw.Write([]string{
+			f.Path,
+			fmt.Sprintf("%d", f.Commits),
+			fmt.Sprintf("%d", f.LinesAdded),
+			fmt.Sprintf("%d", f.LinesRemoved),
+			fmt.Sprintf("%d", f.TotalLines),
+			fmt.Sprintf("%d", f.OriginalLines),
+			fmt.Sprintf("%.2f", f.OriginalPct),
+			fmt.Sprintf("%.2f", f.Score),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// topHotspots returns the first limit hotspots, or all of them when limit is 0.
+func topHotspots(h *report.Hotspots, limit int) []report.Hotspot {
+	if limit > 0 && limit < len(h.Files) {
+		return h.Files[:limit]
+	}
+	return h.Files
+}
@@ -0,0 +1,237 @@
+package visualizer
+
+import (
+	"fmt"
+	"io"
+	"ship-of-theseus/internal/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Markers delimiting the running history table. Everything between them is carried
+// forward from the previous report when a new one is rendered.
+const (
+	historyStartMarker = "<!-- ship-of-theseus:history:start -->"
+	historyEndMarker   = "<!-- ship-of-theseus:history:end -->"
+)
+
+// MarkdownOptions controls the optional parts of a Markdown report.
+type MarkdownOptions struct {
+	CommitHash     string    // Analyzed commit (shown in the header and history)
+	CommitDate     time.Time // Date of the analyzed commit
+	TimelineImage  string    // Path to an SVG timeline to embed; a mermaid chart is used when empty
+	PreviousReport string    // Contents of the previous report, whose history section is carried forward
+}
+
+// WriteMarkdown renders the analysis as a Markdown report suitable for committing
+// to a repository. Unlike Display, the output contains no ANSI escapes or progress
+// output, so it can be written straight to a file such as SHIP_STATUS.md.
+func WriteMarkdown(w io.Writer, analysis *models.CodebaseAnalysis, opts MarkdownOptions) error {
+	originalPct := 0.0
+	if analysis.TotalLines > 0 {
+		originalPct = float64(analysis.OriginalLines) / float64(analysis.TotalLines) * 100.0
+	}
+
+	var b strings.Builder
+
+	// Header
+	b.WriteString("# 🚢 Ship of Theseus Report\n\n")
+	fmt.Fprintf(&b, "**%.1f%% original code remaining**", originalPct)
+	if opts.CommitHash != "" {
+		fmt.Fprintf(&b, " · commit `%s`", shortHash(opts.CommitHash))
+	}
+	if !opts.CommitDate.IsZero() {
+		fmt.Fprintf(&b, " · %s", opts.CommitDate.Format("2006-01-02"))
+	}
+	b.WriteString("\n\n")
+
+	t := tierFor(originalPct)
+	fmt.Fprintf(&b, "> %s %s\n\n", t.Emoji, strings.ReplaceAll(t.Message, "\n   ", " "))
+
+	// Overall statistics
+	b.WriteString("## Overall Statistics\n\n")
+	b.WriteString("| Metric | Value |\n")
+	b.WriteString("|---|---|\n")
+	fmt.Fprintf(&b, "| Total lines of code | %s |\n", formatNumber(analysis.TotalLines))
+	fmt.Fprintf(&b, "| Original lines | %s (%.1f%%) |\n", formatNumber(analysis.OriginalLines), originalPct)
+	fmt.Fprintf(&b, "| Average similarity | %.1f%% |\n", analysis.AverageSimilarity*100)
+	fmt.Fprintf(&b, "| Files analyzed | %s |\n", formatNumber(len(analysis.FileAnalyses)))
+	if analysis.Origin != "" {
+		fmt.Fprintf(&b, "| Compared with | %s |\n", analysis.Origin)
+	}
+	b.WriteString("\n")
+
+	// Timeline
+	if len(analysis.HistoricalSnapshots) > 0 {
+		b.WriteString("## Evolution Timeline\n\n")
+		if opts.TimelineImage != "" {
+			fmt.Fprintf(&b, "![Evolution timeline](%s)\n\n", opts.TimelineImage)
+		} else {
+			writeMermaidTimeline(&b, analysis.HistoricalSnapshots)
+		}
+		writeMarkdownTimelineTable(&b, analysis.HistoricalSnapshots)
+	}
+
+	// File rankings
+	writeMarkdownFileTable(&b, "Most Transformed Files", sortFilesByOriginality(analysis.FileAnalyses, true), 10)
+	writeMarkdownFileTable(&b, "Most Stable Files", sortFilesByOriginality(analysis.FileAnalyses, false), 5)
+
+	// Running history, newest first
+	b.WriteString("## History\n\n")
+	b.WriteString(historyStartMarker + "\n")
+	b.WriteString("| Date | Commit | Original | Avg. Similarity | Lines of Code |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	date := opts.CommitDate
+	if date.IsZero() {
+		date = time.Now()
+	}
+	commitCell := "—"
+	if opts.CommitHash != "" {
+		commitCell = "`" + shortHash(opts.CommitHash) + "`"
+	}
+	fmt.Fprintf(&b, "| %s | %s | %.1f%% | %.1f%% | %s |\n",
+		date.Format("2006-01-02"), commitCell, originalPct, analysis.AverageSimilarity*100, formatNumber(analysis.TotalLines))
+	for _, row := range previousHistoryRows(opts.PreviousReport) {
+		// Re-running on the same commit replaces its row rather than duplicating it
+		if opts.CommitHash != "" && strings.Contains(row, commitCell) {
+			continue
+		}
+		b.WriteString(row + "\n")
+	}
+	b.WriteString(historyEndMarker + "\n\n")
+
+	// Footer
+	b.WriteString("---\n\n")
+	b.WriteString("*\"The ship wherein Theseus and the youth of Athens returned had thirty oars, and was preserved by the Athenians... ")
+	b.WriteString("for they took away the old planks as they decayed, putting in new and stronger timber in their place...\"* — Plutarch\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMermaidTimeline renders snapshots as a mermaid xychart, which GitHub renders natively.
+func writeMermaidTimeline(b *strings.Builder, snapshots []models.Snapshot) {
+	dateFormat := timelineDateFormat(snapshots)
+
+	labels := make([]string, len(snapshots))
+	values := make([]string, len(snapshots))
+	for i, s := range snapshots {
+		labels[i] = fmt.Sprintf("%q", timelineLabel(s, dateFormat))
+		values[i] = fmt.Sprintf("%.1f", s.OriginalPct)
+	}
+
+	b.WriteString("```mermaid\n")
+	b.WriteString("xychart-beta\n")
+	b.WriteString("    title \"Original code remaining\"\n")
+	fmt.Fprintf(b, "    x-axis [%s]\n", strings.Join(labels, ", "))
+	b.WriteString("    y-axis \"Original %\" 0 --> 100\n")
+	fmt.Fprintf(b, "    line [%s]\n", strings.Join(values, ", "))
+	b.WriteString("```\n\n")
+}
+
+// writeMarkdownTimelineTable lists the size, churn and contributors behind each point
+// of the timeline, when the snapshots carry them.
+func writeMarkdownTimelineTable(b *strings.Builder, snapshots []models.Snapshot) {
+	if snapshots[len(snapshots)-1].Lines == 0 {
+		return
+	}
+
+	b.WriteString("| Point | Commit | Original | Lines | Added | Removed | Contributors |\n")
+	b.WriteString("|---|---|---|---|---|---|---|\n")
+	for _, s := range snapshots {
+		fmt.Fprintf(b, "| %s | `%s` | %.1f%% | %s | +%s | -%s | %s |\n",
+			timelineLabel(s, "2006-01-02"), shortHash(s.CommitHash), s.OriginalPct,
+			formatNumber(s.Lines), formatNumber(s.LinesAdded), formatNumber(s.LinesRemoved),
+			formatNumber(s.Contributors))
+	}
+	b.WriteString("\n")
+	b.WriteString("*Lines are counted from git history and include comments and blank lines; ")
+	b.WriteString("originality before the last point is estimated.*\n\n")
+}
+
+// writeMarkdownFileTable renders the first limit files as a ranked table.
+func writeMarkdownFileTable(b *strings.Builder, title string, files []*models.FileAnalysis, limit int) {
+	if len(files) == 0 {
+		return
+	}
+	if len(files) < limit {
+		limit = len(files)
+	}
+
+	fmt.Fprintf(b, "## %s\n\n", title)
+	b.WriteString("| # | File | Original | Lines |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for i, file := range files[:limit] {
+		fmt.Fprintf(b, "| %d | %s | %.1f%% | %s |\n",
+			i+1, markdownCode(file.Path), fileOriginalPct(file), formatNumber(file.TotalLines))
+	}
+	b.WriteString("\n")
+}
+
+// previousHistoryRows extracts the data rows of the history table from an earlier report.
+func previousHistoryRows(report string) []string {
+	start := strings.Index(report, historyStartMarker)
+	end := strings.Index(report, historyEndMarker)
+	if start == -1 || end == -1 || end < start {
+		return nil
+	}
+
+	var rows []string
+	lines := strings.Split(report[start+len(historyStartMarker):end], "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		// Skip the header and separator rows; keep only data rows
+		if !strings.HasPrefix(line, "|") || strings.HasPrefix(line, "| Date ") || strings.HasPrefix(line, "|---") {
+			continue
+		}
+		rows = append(rows, line)
+	}
+	return rows
+}
+
+// markdownCode formats text, such as a file path, as a code span that is safe inside
+// a table cell: pipes are escaped, and the span is fenced with more backticks than
+// the text contains in a row.
+func markdownCode(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	if longest == 0 {
+		return "`" + text + "`"
+	}
+
+	// A space keeps backticks at either end from joining the fence
+	fence := strings.Repeat("`", longest+1)
+	return fence + " " + text + " " + fence
+}
+
+// fileOriginalPct returns the percentage of a file's lines that are original.
+func fileOriginalPct(file *models.FileAnalysis) float64 {
+	if file.TotalLines == 0 {
+		return 0
+	}
+	return float64(file.OriginalLines) / float64(file.TotalLines) * 100.0
+}
+
+// sortFilesByOriginality returns a copy of files ordered by original percentage.
+func sortFilesByOriginality(files []*models.FileAnalysis, ascending bool) []*models.FileAnalysis {
+	sorted := make([]*models.FileAnalysis, len(files))
+	copy(sorted, files)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if ascending {
+			return fileOriginalPct(sorted[i]) < fileOriginalPct(sorted[j])
+		}
+		return fileOriginalPct(sorted[i]) > fileOriginalPct(sorted[j])
+	})
+	return sorted
+}
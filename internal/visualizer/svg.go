@@ -0,0 +1,238 @@
+package visualizer
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"ship-of-theseus/internal/models"
+	"strings"
+)
+
+// SVG chart geometry. The chart is sized for embedding in a README at full width.
+const (
+	svgChartWidth  = 800
+	svgChartHeight = 320
+	svgMarginLeft  = 60
+	svgMarginRight = 30
+	svgMarginTop   = 40
+	svgMarginBot   = 50
+)
+
+// WriteTimelineSVG renders historical snapshots as a standalone SVG line chart.
+// The x-axis is scaled by commit date and the y-axis always spans 0-100% so that
+// charts from different repositories can be compared side by side.
+func WriteTimelineSVG(w io.Writer, snapshots []models.Snapshot) error {
+	if len(snapshots) == 0 {
+		return fmt.Errorf("no snapshots to chart")
+	}
+
+	plotWidth := float64(svgChartWidth - svgMarginLeft - svgMarginRight)
+	plotHeight := float64(svgChartHeight - svgMarginTop - svgMarginBot)
+
+	first := snapshots[0].Date
+	last := snapshots[len(snapshots)-1].Date
+	span := last.Sub(first).Seconds()
+
+	// Map a snapshot to chart coordinates
+	xFor := func(i int) float64 {
+		if len(snapshots) == 1 {
+			return svgMarginLeft + plotWidth/2
+		}
+		if span <= 0 {
+			// All snapshots share a timestamp - fall back to even spacing
+			return svgMarginLeft + plotWidth*float64(i)/float64(len(snapshots)-1)
+		}
+		offset := snapshots[i].Date.Sub(first).Seconds()
+		return svgMarginLeft + plotWidth*offset/span
+	}
+	yFor := func(pct float64) float64 {
+		return svgMarginTop + plotHeight*(1.0-pct/100.0)
+	}
+
+	current := snapshots[len(snapshots)-1].OriginalPct
+	color := tierFor(current).Color
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Verdana,DejaVu Sans,sans-serif" font-size="11">`+"\n",
+		svgChartWidth, svgChartHeight, svgChartWidth, svgChartHeight)
+	b.WriteString(`  <title>Ship of Theseus evolution timeline</title>` + "\n")
+	fmt.Fprintf(&b, `  <rect width="%d" height="%d" fill="#fff"/>`+"\n", svgChartWidth, svgChartHeight)
+	fmt.Fprintf(&b, `  <text x="%d" y="24" font-size="14" font-weight="bold" fill="#333">Original code remaining</text>`+"\n", svgMarginLeft)
+
+	// Horizontal grid lines and y-axis labels every 20%
+	for pct := 0; pct <= 100; pct += 20 {
+		y := yFor(float64(pct))
+		fmt.Fprintf(&b, `  <line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#e5e5e5"/>`+"\n",
+			svgMarginLeft, y, svgChartWidth-svgMarginRight, y)
+		fmt.Fprintf(&b, `  <text x="%d" y="%.1f" text-anchor="end" fill="#666">%d%%</text>`+"\n",
+			svgMarginLeft-8, y+4, pct)
+	}
+
+	// Axes
+	fmt.Fprintf(&b, `  <line x1="%d" y1="%d" x2="%d" y2="%.1f" stroke="#999"/>`+"\n",
+		svgMarginLeft, svgMarginTop, svgMarginLeft, yFor(0))
+	fmt.Fprintf(&b, `  <line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#999"/>`+"\n",
+		svgMarginLeft, yFor(0), svgChartWidth-svgMarginRight, yFor(0))
+
+	// Size, when known, as a dashed series scaled from zero to its maximum
+	sizes, sizeName := snapshotSizes(snapshots)
+	maxLines := 0
+	for _, size := range sizes {
+		maxLines = max(maxLines, size)
+	}
+	if maxLines > 0 && len(snapshots) > 1 {
+		sizePoints := make([]string, len(snapshots))
+		for i, size := range sizes {
+			sizePoints[i] = fmt.Sprintf("%.1f,%.1f", xFor(i), yFor(float64(size)/float64(maxLines)*100))
+		}
+		fmt.Fprintf(&b, `  <polyline fill="none" stroke="#999" stroke-width="1.5" stroke-dasharray="4 3" points="%s"/>`+"\n",
+			strings.Join(sizePoints, " "))
+		fmt.Fprintf(&b, `  <text x="%d" y="24" text-anchor="end" fill="#999">- - %s (up to %s)</text>`+"\n",
+			svgChartWidth-svgMarginRight, sizeName, formatNumber(maxLines))
+	}
+
+	// Data series
+	points := make([]string, len(snapshots))
+	for i, s := range snapshots {
+		points[i] = fmt.Sprintf("%.1f,%.1f", xFor(i), yFor(s.OriginalPct))
+	}
+	fmt.Fprintf(&b, `  <polyline fill="none" stroke="%s" stroke-width="2" points="%s"/>`+"\n",
+		color, strings.Join(points, " "))
+	for i, s := range snapshots {
+		title := fmt.Sprintf("%s %s: %.1f%%", shortHash(s.CommitHash), s.Date.Format("2006-01-02"), s.OriginalPct)
+		if s.Label != "" {
+			title = s.Label + " " + title
+		}
+		if s.Lines > 0 {
+			title += fmt.Sprintf(" · %s lines in files (+%s/-%s since the previous point)", formatNumber(s.Lines), formatNumber(s.LinesAdded), formatNumber(s.LinesRemoved))
+		}
+		if s.TotalLines > 0 {
+			title += fmt.Sprintf(" · %s measured lines of code (%s original)", formatNumber(s.TotalLines), formatNumber(s.OriginalLines))
+		}
+		if s.Contributors > 0 {
+			title += fmt.Sprintf(" · %s contributors", formatNumber(s.Contributors))
+		}
+		fmt.Fprintf(&b, `  <circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>%s</title></circle>`+"\n",
+			xFor(i), yFor(s.OriginalPct), color, html.EscapeString(title))
+	}
+
+	dateFormat := timelineDateFormat(snapshots)
+	if hasTimelineLabels(snapshots) {
+		// Labels (such as release tags) under their points, skipping those that would overlap
+		lastX := -1000.0
+		for i, s := range snapshots {
+			if x := xFor(i); x-lastX >= 60 || i == len(snapshots)-1 {
+				fmt.Fprintf(&b, `  <text x="%.1f" y="%.1f" text-anchor="middle" fill="#666">%s</text>`+"\n",
+					x, yFor(0)+20, html.EscapeString(timelineLabel(s, dateFormat)))
+				lastX = x
+			}
+		}
+	} else {
+		// Date labels for the endpoints
+		fmt.Fprintf(&b, `  <text x="%d" y="%.1f" fill="#666">%s</text>`+"\n",
+			svgMarginLeft, yFor(0)+20, html.EscapeString(first.Format(dateFormat)))
+		fmt.Fprintf(&b, `  <text x="%d" y="%.1f" text-anchor="end" fill="#666">%s</text>`+"\n",
+			svgChartWidth-svgMarginRight, yFor(0)+20, html.EscapeString(last.Format(dateFormat)))
+	}
+
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteBadgeSVG renders a shields.io-style badge such as "original code: 73%".
+// The badge color follows the same bands as the terminal interpretation, applied to
+// the rounded percentage it shows.
+func WriteBadgeSVG(w io.Writer, originalPct float64) error {
+	shown := math.Round(originalPct)
+	label := "original code"
+	value := fmt.Sprintf("%.0f%%", shown)
+	color := tierFor(shown).Color
+
+	// Approximate Verdana 11px glyph widths; good enough for short ASCII labels
+	labelWidth := badgeTextWidth(label) + 10
+	valueWidth := badgeTextWidth(value) + 10
+	totalWidth := labelWidth + valueWidth
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+"\n",
+		totalWidth, label, value)
+	fmt.Fprintf(&b, `  <title>%s: %s</title>`+"\n", label, value)
+	b.WriteString(`  <linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	fmt.Fprintf(&b, `  <clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", totalWidth)
+	b.WriteString(`  <g clip-path="url(#r)">` + "\n")
+	fmt.Fprintf(&b, `    <rect width="%d" height="20" fill="#555"/>`+"\n", labelWidth)
+	fmt.Fprintf(&b, `    <rect x="%d" width="%d" height="20" fill="%s"/>`+"\n", labelWidth, valueWidth, color)
+	fmt.Fprintf(&b, `    <rect width="%d" height="20" fill="url(#s)"/>`+"\n", totalWidth)
+	b.WriteString("  </g>\n")
+	b.WriteString(`  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` + "\n")
+	fmt.Fprintf(&b, `    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>`+"\n", labelWidth/2, label)
+	fmt.Fprintf(&b, `    <text x="%d" y="14">%s</text>`+"\n", labelWidth/2, label)
+	fmt.Fprintf(&b, `    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>`+"\n", labelWidth+valueWidth/2, value)
+	fmt.Fprintf(&b, `    <text x="%d" y="14">%s</text>`+"\n", labelWidth+valueWidth/2, value)
+	b.WriteString("  </g>\n")
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// badgeTextWidth estimates the rendered width in pixels of badge text.
+func badgeTextWidth(text string) int {
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case r == ' ' || r == 'i' || r == 'l' || r == '.':
+			width += 3.5
+		case r == '%' || r == 'm' || r == 'w':
+			width += 10
+		default:
+			width += 7
+		}
+	}
+	return int(width + 0.5)
+}
+
+// timelineDateFormat picks a date layout appropriate for the span of the snapshots.
+func timelineDateFormat(snapshots []models.Snapshot) string {
+	timeSpan := snapshots[len(snapshots)-1].Date.Sub(snapshots[0].Date)
+
+	switch {
+	case timeSpan.Hours() < 24*365: // Less than 1 year - show month and day
+		return "Jan 02"
+	case timeSpan.Hours() < 24*365*3: // 1-3 years - show year-month
+		return "2006-01"
+	default: // More than 3 years - show just year
+		return "2006"
+	}
+}
+
+// timelineLabel returns the x-axis label of a snapshot: its label, such as a release
+// tag, or else its date.
+func timelineLabel(s models.Snapshot, dateFormat string) string {
+	if s.Label != "" {
+		return s.Label
+	}
+	return s.Date.Format(dateFormat)
+}
+
+// hasTimelineLabels reports whether snapshots carry their own labels, which are then
+// shown at every point rather than dates at the endpoints.
+func hasTimelineLabels(snapshots []models.Snapshot) bool {
+	for _, s := range snapshots {
+		if s.Label != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// shortHash abbreviates a commit hash for display.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,97 @@
+package visualizer
+
+import (
+	"encoding/xml"
+	"io"
+	"ship-of-theseus/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+// checkXML fails the test unless svg is well-formed XML.
+func checkXML(t *testing.T, svg string) {
+	t.Helper()
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, svg)
+		}
+	}
+}
+
+func TestWriteBadgeSVG(t *testing.T) {
+	tests := []struct {
+		pct       float64
+		wantText  string
+		wantColor string
+	}{
+		{100, "100%", "#4c1"},
+		{80, "80%", "#4c1"},
+		// 79.6% shows as 80%, so it takes the 80% color
+		{79.6, "80%", "#4c1"},
+		{79.4, "79%", "#97ca00"},
+		{59.5, "60%", "#97ca00"},
+		{39.49, "39%", "#fe7d37"},
+		{0.4, "0%", "#e05d44"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantText, func(t *testing.T) {
+			var b strings.Builder
+			if err := WriteBadgeSVG(&b, tt.pct); err != nil {
+				t.Fatalf("WriteBadgeSVG failed: %v", err)
+			}
+			svg := b.String()
+			checkXML(t, svg)
+			if !strings.Contains(svg, "<title>original code: "+tt.wantText+"</title>") {
+				t.Errorf("badge for %v%% doesn't read %s:\n%s", tt.pct, tt.wantText, svg)
+			}
+			if !strings.Contains(svg, `fill="`+tt.wantColor+`"`) {
+				t.Errorf("badge for %v%% isn't colored %s:\n%s", tt.pct, tt.wantColor, svg)
+			}
+		})
+	}
+}
+
+func TestWriteTimelineSVG(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	snapshots := []models.Snapshot{
+		{CommitHash: "aaaaaaa111", Date: day(1), OriginalPct: 100, Lines: 10},
+		{CommitHash: "bbbbbbb222", Date: day(10), OriginalPct: 70, Label: "v1 <beta>", Lines: 20, LinesAdded: 12, LinesRemoved: 2},
+		{CommitHash: "ccccccc333", Date: day(20), OriginalPct: 55, Lines: 25, LinesAdded: 5, TotalLines: 18, OriginalLines: 10, Contributors: 3},
+	}
+
+	var b strings.Builder
+	if err := WriteTimelineSVG(&b, snapshots); err != nil {
+		t.Fatalf("WriteTimelineSVG failed: %v", err)
+	}
+	svg := b.String()
+	checkXML(t, svg)
+
+	if got := strings.Count(svg, "<circle"); got != len(snapshots) {
+		t.Errorf("chart has %d points, want %d", got, len(snapshots))
+	}
+	for _, want := range []string{
+		"v1 &lt;beta&gt; bbbbbbb 2024-01-10: 70.0%",
+		"25 lines in files (+5/-0 since the previous point)",
+		"18 measured lines of code (10 original)",
+		"3 contributors",
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("chart doesn't contain %q:\n%s", want, svg)
+		}
+	}
+	// Colored by the current originality: 55% is in the 40-60% tier
+	if !strings.Contains(svg, `stroke="#dfb317"`) {
+		t.Errorf("timeline isn't colored by the latest snapshot's tier:\n%s", svg)
+	}
+
+	if err := WriteTimelineSVG(&b, nil); err == nil {
+		t.Error("WriteTimelineSVG without snapshots succeeded, want an error")
+	}
+}
@@ -0,0 +1,332 @@
+package visualizer
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"ship-of-theseus/internal/models"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// timelineGlyphs are the characters a timeline graph is drawn with.
+type timelineGlyphs struct {
+	point, flat, rise, fall, steep  rune // Originality line
+	size                            rune // Code size trail
+	churn                           rune // Churn bars
+	axis, base, corner, arrow, tick rune
+}
+
+var (
+	unicodeGlyphs = timelineGlyphs{
+		point: '●', flat: '─', rise: '╱', fall: '╲', steep: '│',
+		size: '·', churn: '░',
+		axis: '│', base: '─', corner: '└', arrow: '▶', tick: '┬',
+	}
+	asciiGlyphs = timelineGlyphs{
+		point: '*', flat: '-', rise: '/', fall: '\\', steep: '|',
+		size: '.', churn: ':',
+		axis: '|', base: '-', corner: '+', arrow: '>', tick: '+',
+	}
+)
+
+// printTimeline displays an ASCII graph of code evolution over time. Originality is
+// drawn as a connected line; code size and churn are overlaid when the snapshots
+// carry them. The graph fills the terminal and falls back to plain ASCII where
+// Unicode can't be displayed.
+func printTimeline(snapshots []models.Snapshot) {
+	if len(snapshots) < 2 {
+		return
+	}
+
+	fmt.Println("📉 EVOLUTION TIMELINE")
+	fmt.Println()
+
+	width, height := timelineSize()
+	glyphs := unicodeGlyphs
+	if !unicodeTerminal() {
+		glyphs = asciiGlyphs
+	}
+
+	column := func(i int) int {
+		return i * (width - 1) / (len(snapshots) - 1)
+	}
+
+	lines, sizeName := snapshotSizes(snapshots)
+	pcts := make([]float64, len(snapshots))
+	sizes := make([]float64, len(snapshots))
+	churn := make([]float64, len(snapshots))
+	maxSize, maxChurn := 0.0, 0.0
+	for i, s := range snapshots {
+		pcts[i] = s.OriginalPct
+		sizes[i] = float64(lines[i])
+		churn[i] = float64(s.LinesAdded + s.LinesRemoved)
+		maxSize = max(maxSize, sizes[i])
+		maxChurn = max(maxChurn, churn[i])
+	}
+
+	// Find min/max for scaling, with some padding
+	minPct, maxPct := 100.0, 0.0
+	for _, pct := range pcts {
+		minPct, maxPct = min(minPct, pct), max(maxPct, pct)
+	}
+	pctRange := max(maxPct-minPct, 10)
+	minPct = max(minPct-pctRange*0.1, 0)
+	maxPct = min(maxPct+pctRange*0.1, 100)
+
+	// grid[level][x], with level 0 at the bottom
+	grid := make([][]rune, height+1)
+	for level := range grid {
+		grid[level] = []rune(strings.Repeat(" ", width))
+	}
+
+	// Churn bars first, so the lines are drawn over them; they use the lower third
+	if maxChurn > 0 {
+		barHeight := max(height/3, 1)
+		for i, c := range churn {
+			if c > 0 {
+				top := max(int(math.Round(c/maxChurn*float64(barHeight))), 1)
+				for level := 0; level < top; level++ {
+					grid[level][column(i)] = glyphs.churn
+				}
+			}
+		}
+	}
+
+	// Code size as a dotted trail, scaled from zero to its maximum
+	if maxSize > 0 {
+		for x, level := range timelineLevels(sizes, 0, maxSize, width, height) {
+			grid[level][x] = glyphs.size
+		}
+	}
+
+	// Originality as a connected line on top, steep steps filled in vertically
+	levels := timelineLevels(pcts, minPct, maxPct, width, height)
+	for x, level := range levels {
+		next := level
+		if x+1 < width {
+			next = levels[x+1]
+		}
+
+		switch {
+		case next > level:
+			grid[level][x] = glyphs.rise
+			for l := level + 1; l < next; l++ {
+				grid[l][x] = glyphs.steep
+			}
+		case next < level:
+			grid[level][x] = glyphs.fall
+			for l := next + 1; l < level; l++ {
+				grid[l][x] = glyphs.steep
+			}
+		default:
+			grid[level][x] = glyphs.flat
+		}
+	}
+	for i := range snapshots {
+		grid[levels[column(i)]][column(i)] = glyphs.point
+	}
+
+	// Draw graph from top to bottom, with the size axis on the right
+	for level := height; level >= 0; level-- {
+		rowPct := minPct + (maxPct-minPct)*float64(level)/float64(height)
+		row := fmt.Sprintf("   %5.0f%% %c%s", rowPct, glyphs.axis, string(grid[level]))
+		if maxSize > 0 {
+			row += string(glyphs.axis)
+			switch level {
+			case height:
+				row += " " + formatNumber(int(maxSize))
+			case 0:
+				row += " 0"
+			}
+		}
+		fmt.Println(row)
+	}
+
+	// X-axis, with a tick under each label
+	columns, labels := timelineTicks(snapshots, width, column)
+	ticks, labelLine := axisLabels(width, columns, labels)
+	axis := []rune(strings.Repeat(string(glyphs.base), width))
+	for _, x := range ticks {
+		axis[x] = glyphs.tick
+	}
+	fmt.Printf("          %c%s%c\n", glyphs.corner, string(axis), glyphs.arrow)
+	fmt.Printf("           %s\n", labelLine)
+	fmt.Println()
+
+	legend := []string{fmt.Sprintf("%c original code %%", glyphs.point)}
+	if maxSize > 0 {
+		legend = append(legend, fmt.Sprintf("%c %s (right axis)", glyphs.size, sizeName))
+	}
+	if maxChurn > 0 {
+		legend = append(legend, fmt.Sprintf("%c churn (up to %s lines changed between points)", glyphs.churn, formatNumber(int(maxChurn))))
+	}
+	fmt.Printf("   %s\n", strings.Join(legend, "   "))
+	if totals := timelineTotals(snapshots); totals != "" {
+		fmt.Printf("   %s\n", totals)
+	}
+	fmt.Println()
+}
+
+// snapshotSizes returns the size of the codebase at each snapshot and what it counts:
+// the lines counted from git history when the snapshots carry them, otherwise the
+// measured lines of code (as in timelines of stored reports).
+func snapshotSizes(snapshots []models.Snapshot) ([]int, string) {
+	sizes := make([]int, len(snapshots))
+	fromHistory := snapshots[len(snapshots)-1].Lines > 0
+	for i, s := range snapshots {
+		if fromHistory {
+			sizes[i] = s.Lines
+		} else {
+			sizes[i] = s.TotalLines
+		}
+	}
+	if fromHistory {
+		return sizes, "lines"
+	}
+	return sizes, "lines of code"
+}
+
+// timelineTotals summarizes how the codebase grew between the first and last
+// snapshots, or returns "" when the snapshots don't carry sizes.
+func timelineTotals(snapshots []models.Snapshot) string {
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	sizes, sizeName := snapshotSizes(snapshots)
+	if sizes[len(sizes)-1] == 0 {
+		return ""
+	}
+
+	added, removed := 0, 0
+	for _, s := range snapshots {
+		added += s.LinesAdded
+		removed += s.LinesRemoved
+	}
+
+	parts := []string{fmt.Sprintf("%s%s: %s → %s", strings.ToUpper(sizeName[:1]), sizeName[1:],
+		formatNumber(sizes[0]), formatNumber(sizes[len(sizes)-1]))}
+	if added > 0 || removed > 0 {
+		parts = append(parts, fmt.Sprintf("+%s / -%s since the first point", formatNumber(added), formatNumber(removed)))
+	}
+	if last.Contributors > 0 {
+		parts = append(parts, fmt.Sprintf("Contributors: %s → %s", formatNumber(first.Contributors), formatNumber(last.Contributors)))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// timelineLevels interpolates values (one per snapshot, spread evenly across width
+// columns) to a grid level between 0 and height at every column.
+func timelineLevels(values []float64, lo, hi float64, width, height int) []int {
+	levels := make([]int, width)
+	for x := range levels {
+		pos := float64(x) * float64(len(values)-1) / float64(width-1)
+		i := min(int(pos), len(values)-2)
+		v := values[i] + (values[i+1]-values[i])*(pos-float64(i))
+
+		level := height / 2
+		if hi > lo {
+			level = int(math.Round((v - lo) / (hi - lo) * float64(height)))
+		}
+		levels[x] = min(max(level, 0), height)
+	}
+	return levels
+}
+
+// timelineTicks chooses the x-axis labels: every labeled snapshot (such as a release
+// tag), or else dates spread evenly along the axis.
+func timelineTicks(snapshots []models.Snapshot, width int, column func(int) int) ([]int, []string) {
+	dateFormat := timelineDateFormat(snapshots)
+
+	var columns []int
+	var labels []string
+	if hasTimelineLabels(snapshots) {
+		for i, s := range snapshots {
+			columns = append(columns, column(i))
+			labels = append(labels, timelineLabel(s, dateFormat))
+		}
+		return columns, labels
+	}
+
+	// About one date every 16 columns, each that of the nearest snapshot
+	count := max(width/16, 1)
+	for t := 0; t <= count; t++ {
+		x := t * (width - 1) / count
+		i := int(math.Round(float64(x) * float64(len(snapshots)-1) / float64(width-1)))
+		label := snapshots[i].Date.Format(dateFormat)
+
+		// Coarse formats repeat; a repeated date moves to the end rather than showing twice
+		if n := len(labels); n > 0 && labels[n-1] == label {
+			if t == count {
+				columns[n-1] = x
+			}
+			continue
+		}
+		columns = append(columns, x)
+		labels = append(labels, label)
+	}
+	return columns, labels
+}
+
+// axisLabels lays labels out on a line under a width-column x-axis, each starting at
+// its column. The last label is right-aligned to the end of the axis and always
+// shown; others are dropped where they would run into a label already placed.
+// It returns the columns of the labels shown, for tick marks, and the line.
+func axisLabels(width int, columns []int, labels []string) ([]int, string) {
+	line := []rune(strings.Repeat(" ", width))
+	taken := make([]bool, width)
+
+	place := func(start int, label string) bool {
+		text := []rune(label)
+		end := start + len(text)
+		if start < 0 || end > width {
+			return false
+		}
+		for i := max(start-1, 0); i < min(end+1, width); i++ {
+			if taken[i] {
+				return false
+			}
+		}
+		copy(line[start:], text)
+		for i := start; i < end; i++ {
+			taken[i] = true
+		}
+		return true
+	}
+
+	var shown []int
+	if n := len(labels); n > 0 {
+		if place(max(width-len([]rune(labels[n-1])), 0), labels[n-1]) {
+			shown = append(shown, columns[n-1])
+		}
+		for i := 0; i < n-1; i++ {
+			if place(columns[i], labels[i]) {
+				shown = append(shown, columns[i])
+			}
+		}
+	}
+
+	return shown, strings.TrimRight(string(line), " ")
+}
+
+// timelineSize returns the plot area for the terminal stdout is attached to, or the
+// classic 60x10 when it isn't one.
+func timelineSize() (width, height int) {
+	cols, rows, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 60, 10
+	}
+
+	// Leave room for the axis labels on either side
+	return min(max(cols-24, 40), 160), min(max(rows/3, 8), 20)
+}
+
+// unicodeTerminal reports whether the terminal can be expected to display Unicode box
+// drawing: the locale says UTF-8, or no locale is set and the terminal isn't dumb.
+func unicodeTerminal() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := strings.ToLower(os.Getenv(name)); value != "" {
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return os.Getenv("TERM") != "dumb"
+}
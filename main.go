@@ -10,124 +10,478 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"ship-of-theseus/internal/analyzer"
-	"ship-of-theseus/internal/visualizer"
+	"ship-of-theseus/internal/models"
+	"ship-of-theseus/internal/report"
 )
 
 const version = "1.0.0"
 
+// command is a subcommand of the CLI. run receives the arguments after the
+// command name and returns the process exit status.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+var commands = []command{
+	{"analyze", "Analyze the repository and display the full report (default)", runAnalyze},
+	{"timeline", "Show only the evolution timeline", runTimeline},
+	{"file", "Show line-level originality for one file", runFile},
+	{"blame", "Annotate each line of a file with its similarity to origin", runBlame},
+	{"explain", "Show how one line's originality was decided", runExplain},
+	{"explore", "Browse the analysis interactively in the terminal", runExplore},
+	{"diff", "Compare originality between two refs or saved reports", runDiff},
+	{"serve", "Serve the results as a JSON API and web UI", runServe},
+	{"fleet", "Combined report across several repositories", runFleet},
+	{"hotspots", "Rank files by churn and rewriting", runHotspots},
+	{"coupling", "Find files that tend to change together", runCoupling},
+	{"check", "Enforce originality thresholds, for CI", runCheck},
+	{"history", "List runs recorded with analyze --record", runHistory},
+	{"trend", "Plot measured originality across recorded runs", runTrend},
+	{"notes", "Read analysis summaries stored in git notes", runNotes},
+	{"version", "Show version information", runVersion},
+}
+
 func main() {
-	// Define command-line flags
-	var (
-		repoPath   = flag.String("path", ".", "Path to git repository")
-		numWorkers = flag.Int("workers", analyzer.GetDefaultWorkerCount(), "Number of parallel workers")
-		sampleRate = flag.Int("sample", 50, "Sample every Nth commit for history timeline")
-		showVersion = flag.Bool("version", false, "Show version information")
-	)
+	args := os.Args[1:]
+
+	// Without a subcommand (or with only flags) run the analysis, as earlier versions did
+	if len(args) == 0 || (strings.HasPrefix(args[0], "-") && !isHelpFlag(args[0])) {
+		if len(args) > 0 && (args[0] == "-version" || args[0] == "--version") {
+			os.Exit(runVersion(args[1:]))
+		}
+		os.Exit(runAnalyze(args))
+	}
+
+	if args[0] == "help" || isHelpFlag(args[0]) {
+		// "help <command>" shows that command's options
+		if len(args) > 1 {
+			if cmd, ok := findCommand(args[1]); ok {
+				os.Exit(cmd.run([]string{"-h"}))
+			}
+		}
+		printUsage(os.Stdout)
+		return
+	}
+
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q\n\n", args[0])
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+	os.Exit(cmd.run(args[1:]))
+}
+
+// findCommand looks up a subcommand by name.
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func isHelpFlag(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
 
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, `Ship of Theseus v%s - Codebase Evolution Analyzer
+// printUsage lists the subcommands and the flags they share.
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, `Ship of Theseus v%s - Codebase Evolution Analyzer
 
 Usage:
-  ship-of-theseus [options]
+  ship-of-theseus <command> [options]
+  ship-of-theseus [options]            Same as "ship-of-theseus analyze [options]"
 
-Options:
+Commands:
 `, version)
-		flag.PrintDefaults()
-		fmt.Fprintf(os.Stderr, `
-Description:
-  Analyzes a git repository to determine how much "original" code remains.
-  Traces each line from its first appearance to its current state, measuring
-  similarity using Levenshtein distance. Lines with ≥25%% similarity are
-  considered "original"; lines that have changed more are "completely different."
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, `
+Common options:
+  -path string     Path to git repository (default ".")
+  -workers int     Number of parallel workers (default %d)
 
-  Like the ancient Ship of Theseus paradox: if every line of code is eventually
-  modified, is it still the same codebase?
+Run "ship-of-theseus <command> --help" for the options of a command.
 
-Examples:
-  # Analyze current directory
-  ship-of-theseus
+For more information: https://github.com/yourusername/ship-of-theseus
+`, analyzer.GetDefaultWorkerCount())
+}
 
-  # Analyze specific repository with 8 workers
-  ship-of-theseus --path /path/to/repo --workers 8
+// runVersion implements the version subcommand.
+func runVersion(args []string) int {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage:\n  ship-of-theseus version\n")
+	}
+	fs.Parse(args)
 
-  # Use coarser sampling for faster analysis
-  ship-of-theseus --sample 100
+	fmt.Printf("Ship of Theseus v%s\n", version)
+	return 0
+}
+
+// commonFlags are the flags shared by the subcommands that read a repository.
+type commonFlags struct {
+	path    string
+	workers int
+}
 
-Performance:
-  - Small repos (<100 files): <30 seconds
-  - Medium repos (1K-5K files): <5 minutes
-  - Large repos (>10K files): <30 minutes
+// addPathFlag registers --path on fs.
+func addPathFlag(fs *flag.FlagSet) *commonFlags {
+	c := &commonFlags{workers: 1}
+	fs.StringVar(&c.path, "path", ".", "Path to git repository")
+	return c
+}
 
-  Increase --workers for faster analysis on multi-core systems.
-  Increase --sample to reduce analysis time (trades accuracy for speed).
+// addCommonFlags registers --path and --workers on fs.
+func addCommonFlags(fs *flag.FlagSet) *commonFlags {
+	c := addPathFlag(fs)
+	fs.IntVar(&c.workers, "workers", analyzer.GetDefaultWorkerCount(), "Number of parallel workers")
+	return c
+}
 
-For more information: https://github.com/yourusername/ship-of-theseus
-`)
+// resolve validates the common flags and returns the absolute repository path.
+func (c *commonFlags) resolve() (string, error) {
+	if c.workers < 1 {
+		return "", fmt.Errorf("--workers must be at least 1")
+	}
+	return resolveRepoPath(c.path)
+}
+
+// parseArgs parses fs from args, allowing flags after positional arguments
+// ("file main.go --color never"), and returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// repoRelativePath converts a file argument into a path relative to the repository
+// root. Paths that exist from the working directory are resolved from there;
+// anything else is taken to be relative to the root already.
+func repoRelativePath(repoPath, path string) (string, error) {
+	if _, err := os.Stat(path); err == nil || filepath.IsAbs(path) {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return "", fmt.Errorf("Invalid path: %v", err)
+		}
+		rel, err := filepath.Rel(repoPath, absPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("%s is outside the repository %s", path, repoPath)
+		}
+		path = rel
+	}
+	return filepath.ToSlash(filepath.Clean(path)), nil
+}
+
+// colorEnabled interprets a --color flag value. "auto" colors only when stdout is a
+// terminal and NO_COLOR is unset.
+func colorEnabled(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+			return false, nil
+		}
+		return isTerminal(os.Stdout), nil
+	default:
+		return false, fmt.Errorf("--color must be one of: auto, always, never")
+	}
+}
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// watchFlags are the flags of subcommands that can keep running as commits arrive.
+type watchFlags struct {
+	enabled  bool
+	interval time.Duration
+}
+
+// addWatchFlags registers --watch and --watch-interval on fs.
+func addWatchFlags(fs *flag.FlagSet, what string) *watchFlags {
+	w := &watchFlags{}
+	fs.BoolVar(&w.enabled, "watch", false, "Keep running and "+what+" when new commits arrive")
+	fs.DurationVar(&w.interval, "watch-interval", 5*time.Second, "How often --watch checks HEAD and refs for new commits")
+	return w
+}
+
+// validate checks the watch flags.
+func (w *watchFlags) validate() error {
+	if w.enabled && w.interval <= 0 {
+		return fmt.Errorf("--watch-interval must be positive")
+	}
+	return nil
+}
+
+// originFlags select what lines are compared against (see analyzer.Origin).
+type originFlags struct {
+	originAt  string
+	upstream  string
+	mergeBase bool
+}
+
+// addOriginFlags registers --origin-at, --upstream and --merge-base on fs.
+func addOriginFlags(fs *flag.FlagSet) *originFlags {
+	o := &originFlags{}
+	fs.StringVar(&o.originAt, "origin-at", "", "Compare with the codebase at this ref or date (YYYY-MM-DD) instead of each file's first commit")
+	fs.StringVar(&o.upstream, "upstream", "", "Compare with this ref (e.g. upstream/main) instead of each file's first commit")
+	fs.BoolVar(&o.mergeBase, "merge-base", false, "With --upstream, compare with the merge-base of HEAD and the upstream ref")
+	return o
+}
+
+// resolve turns the origin flags into an analyzer.Origin for the repository.
+func (o *originFlags) resolve(repoPath string) (analyzer.Origin, error) {
+	if o.originAt != "" {
+		if o.upstream != "" || o.mergeBase {
+			return analyzer.Origin{}, fmt.Errorf("--origin-at cannot be combined with --upstream or --merge-base")
+		}
+		commit, err := resolveOriginAt(repoPath, o.originAt)
+		if err != nil {
+			return analyzer.Origin{}, fmt.Errorf("--origin-at: %v", err)
+		}
+		return analyzer.CommitOrigin(repoPath, commit, fmt.Sprintf("%s (%s)", o.originAt, commit[:7]))
+	}
+
+	if o.upstream == "" {
+		if o.mergeBase {
+			return analyzer.Origin{}, fmt.Errorf("--merge-base requires --upstream")
+		}
+		return analyzer.Origin{}, nil
+	}
+
+	commit, err := analyzer.ResolveCommit(repoPath, o.upstream)
+	if err != nil {
+		return analyzer.Origin{}, fmt.Errorf("--upstream: %v", err)
+	}
+
+	name := o.upstream
+	if o.mergeBase {
+		if commit, err = analyzer.MergeBase(repoPath, "HEAD", commit); err != nil {
+			return analyzer.Origin{}, fmt.Errorf("--merge-base: %v", err)
+		}
+		name = "merge-base of HEAD and " + o.upstream
+	}
+
+	return analyzer.CommitOrigin(repoPath, commit, fmt.Sprintf("%s (%s)", name, commit[:7]))
+}
+
+// resolveOriginAt resolves an --origin-at value: a ref, or else a date, which stands
+// for the last commit made by the end of that day.
+func resolveOriginAt(repoPath, value string) (string, error) {
+	if commit, err := analyzer.ResolveCommit(repoPath, value); err == nil {
+		return commit, nil
+	}
+
+	t, err := parseDate(value, true)
+	if err != nil {
+		return "", fmt.Errorf("%q is neither a ref nor a date (YYYY-MM-DD or RFC 3339)", value)
+	}
+
+	commit, err := analyzer.CommitBefore(repoPath, "HEAD", t, false)
+	if err == nil && commit == "" {
+		err = fmt.Errorf("no commits at or before %s", value)
+	}
+	return commit, err
+}
+
+// parseDate parses a YYYY-MM-DD (local time) or RFC 3339 date. A bare day stands for
+// its start, or for its end when endOfDay is set.
+func parseDate(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1).Add(-time.Second)
+		}
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// historyFlags select the history an analysis looks at: a span of time, a branch,
+// and whether merged branches count or only the mainline does.
+type historyFlags struct {
+	since       string
+	until       string
+	branch      string
+	firstParent bool
+}
+
+// addHistoryFlags registers --since, --until, --branch and --first-parent on fs.
+func addHistoryFlags(fs *flag.FlagSet) *historyFlags {
+	h := &historyFlags{}
+	fs.StringVar(&h.since, "since", "", "Start of the time window (YYYY-MM-DD): the timeline starts here and lines are compared with their first version within the window")
+	fs.StringVar(&h.until, "until", "", "End of the time window (YYYY-MM-DD): the timeline ends here")
+	fs.StringVar(&h.branch, "branch", "", "Follow this branch or ref instead of every ref (default: all refs, analyzed at HEAD)")
+	fs.BoolVar(&h.firstParent, "first-parent", false, "Follow only the first parent of merges, so merged branches count as their merge commit")
+	return h
+}
+
+// filter parses the flags into a commit filter; the zero filter when none are set.
+func (h *historyFlags) filter() (analyzer.CommitFilter, error) {
+	f := analyzer.CommitFilter{Branch: h.branch, FirstParent: h.firstParent}
+	var err error
+	if h.since != "" {
+		if f.Since, err = parseDate(h.since, false); err != nil {
+			return f, fmt.Errorf("--since must be a date (YYYY-MM-DD or RFC 3339)")
+		}
+	}
+	if h.until != "" {
+		if f.Until, err = parseDate(h.until, true); err != nil {
+			return f, fmt.Errorf("--until must be a date (YYYY-MM-DD or RFC 3339)")
+		}
 	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && f.Until.Before(f.Since) {
+		return f, fmt.Errorf("--until must not be before --since")
+	}
+	return f, nil
+}
+
+// checkout returns the directory holding the code at the end of the selected history:
+// the repository itself, or a temporary worktree of the branch or of the last commit
+// made by --until. Call the returned function to remove the worktree once done.
+func (h *historyFlags) checkout(repoPath string, f analyzer.CommitFilter) (string, func(), error) {
+	ref := "HEAD"
+	if f.Branch != "" {
+		ref = f.Branch
+	}
+
+	commit, err := analyzer.ResolveCommit(repoPath, ref)
+	if err != nil {
+		return "", nil, fmt.Errorf("--branch: %v", err)
+	}
+	if !f.Until.IsZero() {
+		commit, err = analyzer.CommitBefore(repoPath, ref, f.Until, f.FirstParent)
+		if err == nil && commit == "" {
+			err = fmt.Errorf("no commits on %s at or before --until %s", ref, h.until)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if head, err := analyzer.ResolveCommit(repoPath, "HEAD"); err == nil && head == commit {
+		return repoPath, func() {}, nil
+	}
+	return analyzer.AddWorktree(repoPath, commit)
+}
+
+// timelineFlags choose the commits a timeline is plotted at, together with --sample.
+type timelineFlags struct {
+	mode     string
+	tagGlob  string
+	interval string
+}
 
-	flag.Parse()
+// addTimelineFlags registers --timeline, --tag-glob and --interval on fs.
+func addTimelineFlags(fs *flag.FlagSet) *timelineFlags {
+	t := &timelineFlags{}
+	fs.StringVar(&t.mode, "timeline", "commits", "Plot the timeline at: commits (every --sample'th commit) or tags (each release tag)")
+	fs.StringVar(&t.tagGlob, "tag-glob", "", "With --timeline tags, only tags matching this glob (e.g. \"v*\")")
+	fs.StringVar(&t.interval, "interval", "", "Plot the last commit of each week, month or quarter instead of every --sample'th commit")
+	return t
+}
+
+// sampling turns the timeline flags and the --sample rate into an analyzer.Sampling.
+func (t *timelineFlags) sampling(sampleRate int) (analyzer.Sampling, error) {
+	if t.interval != "" && !slices.Contains(analyzer.Intervals, t.interval) {
+		return analyzer.Sampling{}, fmt.Errorf("--interval must be one of: %s", strings.Join(analyzer.Intervals, ", "))
+	}
 
-	// Handle version flag
-	if *showVersion {
-		fmt.Printf("Ship of Theseus v%s\n", version)
-		os.Exit(0)
+	switch t.mode {
+	case "commits":
+		if t.tagGlob != "" {
+			return analyzer.Sampling{}, fmt.Errorf("--tag-glob requires --timeline tags")
+		}
+		if t.interval != "" {
+			return analyzer.Sampling{Interval: t.interval}, nil
+		}
+		if sampleRate < 1 {
+			return analyzer.Sampling{}, fmt.Errorf("--sample must be at least 1")
+		}
+		return analyzer.Sampling{Every: sampleRate}, nil
+	case "tags":
+		if t.interval != "" {
+			return analyzer.Sampling{}, fmt.Errorf("--interval cannot be combined with --timeline tags")
+		}
+		return analyzer.Sampling{Tags: true, TagGlob: t.tagGlob}, nil
+	default:
+		return analyzer.Sampling{}, fmt.Errorf("--timeline must be one of: commits, tags")
 	}
+}
 
-	// Validate and resolve repository path
-	absPath, err := filepath.Abs(*repoPath)
+// newReport summarizes an analysis as a report tagged with HEAD and the tool version.
+func newReport(repoPath string, analysis *models.CodebaseAnalysis) *report.Report {
+	r := report.New(analysis)
+	r.ToolVersion = version
+	r.Settings = &report.Settings{
+		SimilarityThreshold: analyzer.MinimumSimilarityThreshold,
+		LineMovementWindow:  analyzer.LineMovementWindow,
+		Origin:              analysis.Origin,
+	}
+	if head, err := analyzer.GetHeadCommit(repoPath); err == nil {
+		r.CommitHash = head.Hash
+		r.CommitDate = &head.Date
+	}
+	return r
+}
+
+// resolveRepoPath converts path to an absolute path and verifies that it is a git repository.
+func resolveRepoPath(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Invalid path: %v\n", err)
-		os.Exit(1)
+		return "", fmt.Errorf("Invalid path: %v", err)
 	}
 
 	// Check if directory exists
 	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: Directory does not exist: %s\n", absPath)
-		os.Exit(1)
+		return "", fmt.Errorf("Directory does not exist: %s", absPath)
 	}
 
 	// Check if it's a git repository
 	gitDir := filepath.Join(absPath, ".git")
 	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: Not a git repository: %s\n", absPath)
-		fmt.Fprintf(os.Stderr, "       (no .git directory found)\n")
-		os.Exit(1)
-	}
-
-	// Validate parameters
-	if *numWorkers < 1 {
-		fmt.Fprintf(os.Stderr, "Error: --workers must be at least 1\n")
-		os.Exit(1)
+		return "", fmt.Errorf("Not a git repository: %s\n       (no .git directory found)", absPath)
 	}
 
-	if *sampleRate < 1 {
-		fmt.Fprintf(os.Stderr, "Error: --sample must be at least 1\n")
-		os.Exit(1)
-	}
-
-	// Print startup message
-	fmt.Printf("🚢 Ship of Theseus v%s\n", version)
-	fmt.Printf("Analyzing repository: %s\n", absPath)
-	fmt.Printf("Workers: %d | Sample rate: every %d commits\n\n", *numWorkers, *sampleRate)
+	return absPath, nil
+}
 
-	// Run the analysis
-	analysis, err := analyzer.AnalyzeRepository(absPath, *numWorkers)
+// writeFile creates (or truncates) a file and fills it using the given render function.
+func writeFile(path string, render func(w io.Writer) error) error {
+	f, err := os.Create(path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\nError during analysis: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Generate historical snapshots
-	fmt.Println("\nGenerating historical timeline...")
-	if err := analyzer.AddSnapshotsToAnalysis(analysis, absPath, *sampleRate); err != nil {
-		// Non-fatal: continue without snapshots
-		fmt.Printf("Warning: Could not generate historical timeline: %v\n", err)
+	if err := render(f); err != nil {
+		f.Close()
+		return err
 	}
 
-	// Display results
-	visualizer.Display(analysis)
+	return f.Close()
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/report"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// runNotes implements the notes subcommand, which reads summaries stored with --notes.
+func runNotes(args []string) int {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, `Usage:
+  ship-of-theseus notes timeline [options]        Plot measured originality from %s
+  ship-of-theseus notes show [options] [commit]   Print the summary stored on a commit (default: HEAD)
+
+Fetch and push notes explicitly; git does not transfer them by default:
+  git fetch origin %s:%s
+  git push origin %s
+`, analyzer.NotesRef, analyzer.NotesRef, analyzer.NotesRef, analyzer.NotesRef)
+	}
+
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	switch args[0] {
+	case "timeline":
+		return runNotesTimeline(args[1:])
+	case "show":
+		return runNotesShow(args[1:])
+	case "-h", "-help", "--help", "help":
+		usage()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown notes command %q\n\n", args[0])
+		usage()
+		return 2
+	}
+}
+
+// runNotesTimeline plots the summaries stored in git notes as a measured timeline.
+func runNotesTimeline(args []string) int {
+	fs := flag.NewFlagSet("notes timeline", flag.ExitOnError)
+	common := addPathFlag(fs)
+	timelineSVG := fs.String("svg-timeline", "", "Also write the timeline as an SVG chart to this file")
+	fs.Parse(args)
+
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	notes, err := analyzer.GetAllNotes(absPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not read notes: %v\n", err)
+		return 1
+	}
+
+	reports, skipped := report.FromNotes(notes)
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: Skipped %d note(s) that are not analysis summaries\n", skipped)
+	}
+
+	visualizer.DisplayTrend(reports)
+
+	if *timelineSVG != "" && len(reports) > 0 {
+		if err := writeFile(*timelineSVG, func(w io.Writer) error {
+			return visualizer.WriteTimelineSVG(w, report.ToSnapshots(reports))
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not write timeline chart: %v\n", err)
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// runNotesShow prints the summary stored on one commit.
+func runNotesShow(args []string) int {
+	fs := flag.NewFlagSet("notes show", flag.ExitOnError)
+	common := addPathFlag(fs)
+	fs.Parse(args)
+
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	commit := "HEAD"
+	if fs.NArg() > 0 {
+		commit = fs.Arg(0)
+	}
+
+	note, err := analyzer.GetNote(absPath, commit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Pretty-print summaries; pass anything else through untouched
+	var r report.Report
+	if json.Unmarshal([]byte(note), &r) == nil {
+		if data, err := json.MarshalIndent(r, "", "  "); err == nil {
+			note = string(data) + "\n"
+		}
+	}
+	fmt.Print(note)
+	return 0
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/models"
+	"ship-of-theseus/internal/report"
+	"ship-of-theseus/internal/server"
+)
+
+// runServe implements the serve subcommand: the JSON API and web UI.
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	var (
+		addr       = fs.String("addr", "localhost:8080", "Address to listen on")
+		reportFile = fs.String("report", "", "Serve a saved report (see analyze --save-report) instead of analyzing")
+		sampleRate = fs.Int("sample", 50, "Sample every Nth commit for history timeline")
+		memBudget  = fs.Int("memory-budget", 512, "MB of line-level detail to keep in memory before spilling to a temp file (0 = unlimited)")
+	)
+	watch := addWatchFlags(fs, "update the served data")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage:
+  ship-of-theseus serve [options]
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Analyzes the repository (or loads a saved report) and serves the results:
+
+    GET /                          Web UI
+    GET /api/summary               Totals for the repository
+    GET /api/files                 Per-file summaries (?prefix=, sort=path|originality|similarity|size,
+                                   order=asc|desc, limit=)
+    GET /api/files/{path}/lines    Line-level detail for one file (not available for saved reports)
+    GET /api/timeline              Historical snapshots: originality, lines of code, churn
+                                   and contributors (a saved report's own timeline, if it has one)
+
+  With --watch, files touched by new commits are re-analyzed in the background
+  and the API serves the updated results; the web UI picks them up on its own.
+`)
+	}
+
+	fs.Parse(args)
+
+	if *sampleRate < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --sample must be at least 1\n")
+		return 1
+	}
+
+	if *memBudget < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --memory-budget cannot be negative\n")
+		return 1
+	}
+
+	if err := watch.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if watch.enabled && *reportFile != "" {
+		fmt.Fprintf(os.Stderr, "Error: --watch cannot be used with --report\n")
+		return 1
+	}
+
+	fmt.Printf("🚢 Ship of Theseus v%s\n", version)
+
+	srv := server.New(nil)
+	if *reportFile != "" {
+		r, err := report.Load(*reportFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not load report: %v\n", err)
+			return 1
+		}
+		data := &server.Data{Report: r, Timeline: r.Timeline}
+
+		// Generating a timeline needs the repository; a report alone can be served without one
+		if absPath, err := common.resolve(); err == nil && len(r.Timeline) == 0 {
+			fmt.Println("Generating historical timeline...")
+			if data.Timeline, err = analyzer.GenerateHistoricalSnapshots(absPath, analyzer.Sampling{Every: *sampleRate}, r.TotalLines, r.OriginalLines, analyzer.CommitFilter{}); err != nil {
+				fmt.Printf("Warning: Could not generate historical timeline: %v\n", err)
+			}
+		}
+		srv.Update(data)
+	} else {
+		absPath, err := common.resolve()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+
+		spool := analyzer.NewLineSpool(int64(*memBudget) * 1024 * 1024)
+		defer spool.Remove()
+
+		// Note what is being analyzed first, so --watch can't miss commits made meanwhile
+		var state analyzer.RepoState
+		if watch.enabled {
+			if state, err = analyzer.GetRepoState(absPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+		}
+
+		opts := analyzer.Options{
+			NumWorkers: common.workers,
+			Sinks:      []analyzer.ResultSink{spool},
+		}
+
+		fmt.Printf("Analyzing repository: %s\n\n", absPath)
+		analysis, err := analyzer.AnalyzeRepositoryWithOptions(absPath, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\nError during analysis: %v\n", err)
+			return 1
+		}
+
+		fmt.Println("Generating historical timeline...")
+		if err := analyzer.AddSnapshotsToAnalysis(analysis, absPath, analyzer.Sampling{Every: *sampleRate}, analyzer.CommitFilter{}); err != nil {
+			fmt.Printf("Warning: Could not generate historical timeline: %v\n", err)
+		}
+		srv.Update(serverData(absPath, analysis, spool))
+
+		if watch.enabled {
+			go watchServer(srv, absPath, analysis, state, opts, spool, analyzer.Sampling{Every: *sampleRate}, watch.interval)
+		}
+	}
+
+	fmt.Printf("\nServing on http://%s (Ctrl+C to stop)\n", *addr)
+	if err := http.ListenAndServe(*addr, srv); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// watchServer re-analyzes files touched by new commits and swaps the results into
+// the server. Re-analyzed files' line detail goes to the spool through opts. It runs
+// until re-analysis fails, then exits the process.
+func watchServer(srv *server.Server, repoPath string, analysis *models.CodebaseAnalysis, state analyzer.RepoState, opts analyzer.Options, spool *analyzer.LineSpool, sampling analyzer.Sampling, interval time.Duration) {
+	fmt.Printf("Watching for new commits every %s\n", interval)
+	err := analyzer.Watch(repoPath, state, interval, func(change analyzer.Change) error {
+		// Files are always compared with their first commit, an origin that can't move
+		updated, err := refreshAnalysis(repoPath, analysis, change, &opts, nil, sampling, analyzer.CommitFilter{})
+		if err != nil {
+			return err
+		}
+		analysis = updated
+		srv.Update(serverData(repoPath, analysis, spool))
+
+		// Lines of the previous analysis are released only once it is no longer served;
+		// a request still reading it finds no lines rather than another version's
+		if err := spool.Forget(change.Deleted...); err != nil {
+			return err
+		}
+		fmt.Println(describeChange(change))
+		return nil
+	})
+
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	spool.Remove()
+	os.Exit(1)
+}
+
+// serverData packages an analysis for the server. Line-level detail that isn't held
+// in memory comes from the spool, which must outlive the returned data.
+func serverData(repoPath string, analysis *models.CodebaseAnalysis, spool *analyzer.LineSpool) *server.Data {
+	files := make(map[string]*models.FileAnalysis, len(analysis.FileAnalyses))
+	for _, file := range analysis.FileAnalyses {
+		files[file.Path] = file
+	}
+
+	return &server.Data{
+		Report:   newReport(repoPath, analysis),
+		Timeline: analysis.HistoricalSnapshots,
+		Lines: func(path string) ([]*models.LineHistory, error) {
+			file, ok := files[path]
+			if !ok {
+				return nil, server.ErrNoLines
+			}
+			if file.LineHistories != nil {
+				return file.LineHistories, nil
+			}
+			lines, spilled, err := spool.Lines(file)
+			if !spilled {
+				return nil, server.ErrNoLines
+			}
+			return lines, err
+		},
+	}
+}
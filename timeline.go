@@ -0,0 +1,179 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"ship-of-theseus/internal/analyzer"
+	"ship-of-theseus/internal/report"
+	"ship-of-theseus/internal/visualizer"
+)
+
+// runTimeline implements the timeline subcommand: only the evolution timeline,
+// without displaying (or, when a stored summary is available, running) the full analysis.
+func runTimeline(args []string) int {
+	fs := flag.NewFlagSet("timeline", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	var (
+		sampleRate  = fs.Int("sample", 50, "Sample every Nth commit for history timeline")
+		timelineSVG = fs.String("svg-timeline", "", "Also write the timeline as an SVG chart to this file")
+		currentFile = fs.String("current", "", "Report file with the current originality (skips the analysis)")
+		fresh       = fs.Bool("fresh", false, "Always analyze HEAD, even if a stored summary exists")
+	)
+	scope := addHistoryFlags(fs)
+	timeline := addTimelineFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage:
+  ship-of-theseus timeline [options]
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Description:
+  Plots the evolution timeline. Earlier points are estimated from commit age and
+  churn and anchored to the current originality, which is taken from --current,
+  a git note on HEAD (analyze --notes) or a recorded run of HEAD (analyze --record).
+  Only when none of these exist is the repository analyzed.
+
+  By default the timeline follows every ref. --branch follows one branch instead,
+  and --first-parent only its mainline. --since and --until plot only the commits
+  made within the window. The end of such a timeline is anchored to the code at
+  the end of that history, compared with the code as of --since, and is measured
+  afresh unless --current is given.
+
+Examples:
+  ship-of-theseus timeline --sample 20
+  ship-of-theseus timeline --branch main --first-parent
+  ship-of-theseus timeline --timeline tags --tag-glob "v*"
+  ship-of-theseus timeline --interval quarter
+  ship-of-theseus timeline --since 2024-01-01 --until 2024-12-31
+`)
+	}
+
+	fs.Parse(args)
+
+	absPath, err := common.resolve()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	sampling, err := timeline.sampling(*sampleRate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	filter, err := scope.filter()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var current *report.Report
+	var source string
+	if filter == (analyzer.CommitFilter{}) || *currentFile != "" {
+		current, source, err = currentReport(absPath, *currentFile, *fresh, common.workers)
+	} else {
+		// Stored summaries measure HEAD against each file's first commit along all history
+		current, source, err = scopedReport(absPath, scope, filter, common.workers)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Current originality: %.1f%% (%s)\n\n", current.OriginalPct, source)
+
+	snapshots, err := analyzer.GenerateHistoricalSnapshots(absPath, sampling, current.TotalLines, current.OriginalLines, filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not generate historical timeline: %v\n", err)
+		return 1
+	}
+
+	visualizer.DisplayTimeline(snapshots)
+
+	if *timelineSVG != "" {
+		if err := writeFile(*timelineSVG, func(w io.Writer) error {
+			return visualizer.WriteTimelineSVG(w, snapshots)
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not write timeline chart: %v\n", err)
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// currentReport finds the summary of HEAD without analyzing when possible: an explicit
+// report file, then a git note on HEAD, then a recorded run of HEAD. It returns the
+// report and a description of where it came from.
+func currentReport(repoPath, reportFile string, fresh bool, numWorkers int) (*report.Report, string, error) {
+	if reportFile != "" {
+		r, err := report.Load(reportFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("Could not load report: %v", err)
+		}
+		return r, reportFile, nil
+	}
+
+	if !fresh {
+		if head, err := analyzer.GetHeadCommit(repoPath); err == nil {
+			if note, err := analyzer.GetNote(repoPath, head.Hash); err == nil {
+				if reports, _ := report.FromNotes(map[string]string{head.Hash: note}); len(reports) == 1 {
+					return reports[0], "git note on " + describeReport(reports[0]), nil
+				}
+			}
+
+			if reports, err := loadHistory(repoPath, ""); err == nil {
+				for i := len(reports) - 1; i >= 0; i-- {
+					if reports[i].CommitHash == head.Hash {
+						return reports[i], "recorded run of " + describeReport(reports[i]), nil
+					}
+				}
+			}
+		}
+	}
+
+	fmt.Printf("Analyzing repository: %s\n\n", repoPath)
+	r, err := analyzeReport(repoPath, numWorkers)
+	if err != nil {
+		return nil, "", fmt.Errorf("analysis failed: %v", err)
+	}
+	fmt.Println()
+	return r, "measured now", nil
+}
+
+// scopedReport analyzes the code at the end of the selected history, comparing each
+// file with its first version within it.
+func scopedReport(repoPath string, scope *historyFlags, filter analyzer.CommitFilter, numWorkers int) (*report.Report, string, error) {
+	analysisPath, removeCheckout, err := scope.checkout(repoPath, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	defer removeCheckout()
+
+	origin := analyzer.Origin{FirstParent: filter.FirstParent}
+	if !filter.Since.IsZero() {
+		if origin, err = analyzer.SinceOrigin(analysisPath, filter); err != nil {
+			return nil, "", err
+		}
+	}
+
+	fmt.Printf("Analyzing repository: %s\n\n", repoPath)
+	analysis, err := analyzer.AnalyzeRepositoryWithOptions(analysisPath, analyzer.Options{
+		NumWorkers:        numWorkers,
+		Origin:            origin,
+		DropLineHistories: true,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("analysis failed: %v", err)
+	}
+	fmt.Println()
+
+	r := newReport(analysisPath, analysis)
+	return r, "measured now for " + describeReport(r), nil
+}